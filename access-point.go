@@ -1,349 +1,191 @@
 package arubaos
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"reflect"
+	"sort"
 	"strings"
+	"time"
 )
 
-// Intf the Aruba AP Interface Information
-type Intf struct {
-	Duplex    string `json:"Duplex"`
-	MAC       string `json:"MAC"`
-	Oper      string `json:"Oper"`
-	Port      string `json:"Port"`
-	RXBytes   string `json:"RX-Bytes"`
-	RXPackets string `json:"RX-Packets"`
-	Speed     string `json:"Speed"`
-	TXBytes   string `json:"TX-Bytes"`
-	TXPackets string `json:"TX-Packets"`
+// APDetails the information from "show ap details"
+type APDetails struct {
+	AP
+	Uptime           time.Duration
+	BootVersion      string
+	SoftwareVersion  string
+	InstallationMode string // indoor or outdoor
+	LocationName     string
+	Radios           []APRadioDetails
+	// Sections holds every Item/Value pair, keyed by section name without the AP name,
+	// e.g. Sections["Basic Information"]["Group"]
+	Sections map[string]map[string]string
 }
 
-// GetApPortStatus retrieves Interface statistics of an AP
-// This Command Must be run from a Controller *NOT MM
-func (c *Client) GetApPortStatus(mac string) (Intf, error) {
-	if c.cookie == nil {
-		return Intf{}, fmt.Errorf(loginWarning)
-	}
-	req, err := c.genGetReq("/configuration/showcommand")
-	if err != nil {
-		return Intf{}, fmt.Errorf("%v", err)
-	}
-	cmd := fmt.Sprintf("show ap port status wired-mac %s", mac)
-	qs := map[string]string{"command": cmd}
-	c.updateReq(req, qs)
-	res, err := c.http.Do(req)
-	if err != nil {
-		return Intf{}, fmt.Errorf("%v", err)
-	}
-	defer res.Body.Close()
-	// Debug purposes
-	// d, _ := ioutil.ReadAll(res.Body)
-	// fmt.Println(string(d))
-	var intf Intf
+// APRadioDetails the operating information of one radio in "show ap details"
+type APRadioDetails struct {
+	Radio   string // e.g. "Radio 0"
+	Band    string
+	Channel string
+	EIRP    float64 // dBm
+	Mode    string  // e.g. AP or Air Monitor
+}
 
-	// This Block of Code map[string][]Slice
-	// Is Used Because the Property/Field of the returned
-	// JSON Object is Dynamic/Non-Deterministic, so IT needs
-	// To Be PARSED and Stripped OFF
-	var mintfs map[string][]interface{}
-	if err = json.NewDecoder(res.Body).Decode(&mintfs); err != nil {
-		return Intf{}, fmt.Errorf("error parsing resp body: %v", err)
-	}
-	for k, intfs := range mintfs {
-		// Ignore these Fields
-		if k == "_meta" || k == "_data" {
-			continue
+// item returns the value of the first of items that is found, looking in the basic, hardware and
+// operating sections before any other section except the radios
+func (d *APDetails) item(items ...string) string {
+	names := []string{"Basic Information", "Hardware Information", "Operating Information"}
+	var others []string
+	for name := range d.Sections {
+		if !strings.HasPrefix(name, "Radio ") && name != names[0] && name != names[1] && name != names[2] {
+			others = append(others, name)
 		}
-		for _, m := range intfs {
-			v := reflect.ValueOf(m)
-			if v.Kind() == reflect.Map {
-				for _, key := range v.MapKeys() {
-					k := key.String()
-					l := v.MapIndex(key)
-					val := l.Interface().(string)
-					switch k {
-					case "Duplex":
-						intf.Duplex = val
-					case "MAC":
-						intf.MAC = val
-					case "Speed":
-						intf.Speed = val
-					case "Oper":
-						if val != "up" {
-							intf = Intf{}
-						}
-						intf.Oper = val
-					case "Port":
-						intf.Port = val
-					case "RX-Bytes":
-						intf.RXBytes = val
-					case "RX-Packets":
-						intf.RXPackets = val
-					case "TX-Bytes":
-						intf.TXBytes = val
-					case "TX-Packets":
-						intf.TXPackets = val
-					}
-				}
-				if intf.Oper == "up" {
-					break
-				}
+	}
+	sort.Strings(others)
+	names = append(names, others...)
+	for _, item := range items {
+		for _, name := range names {
+			if val := d.Sections[name][item]; val != "" {
+				return val
 			}
 		}
 	}
-	return intf, nil
-}
-
-// APLldp the properties of a Neighbor Connected to the AP
-type APLldp struct {
-	APName         string `json:"AP"`
-	RemoteHostname string `json:"Chassis Name/ID"`
-	RemoteIP       string `json:"Mgmt. Address"`
-	RemoteIntfDesc string `json:"Port Desc"`
-	RemoteIntf     string `json:"Port ID"`
+	return ""
 }
 
-// GetApLLDPInfo gets LLDP Info of Device Connecting to the AP
-// This Command MUST be run from the Controller *NOT MM
-func (c *Client) GetApLLDPInfo(apName string) (APLldp, error) {
-	if c.cookie == nil {
-		return APLldp{}, fmt.Errorf(loginWarning)
+// GetApDetails returns "show ap details" for one AP, target must select the AP by Name or WiredMac
+// This Command must be run from the Controller the AP is Registered with
+// ErrApNotFound is returned if the controller does not know the AP
+func (c *Client) GetApDetails(ctx context.Context, target ApTarget) (APDetails, error) {
+	if err := target.validate(); err != nil {
+		return APDetails{}, err
 	}
-	req, err := c.genGetReq("/configuration/showcommand")
-	if err != nil {
-		return APLldp{}, fmt.Errorf("%v", err)
-	}
-	cmd := fmt.Sprintf("show ap lldp neighbors ap-name %s", apName)
-	qs := map[string]string{"command": cmd}
-	c.updateReq(req, qs)
-	res, err := c.http.Do(req)
+	var cmd string
+	switch {
+	case target.Name != "":
+		cmd = fmt.Sprintf("show ap details ap-name %s", target.Name)
+	case target.WiredMac != "":
+		cmd = fmt.Sprintf("show ap details wired-mac %s", target.WiredMac)
+	default:
+		return APDetails{}, fmt.Errorf("%w: GetApDetails needs either a name or a wired MAC", ErrInvalidTarget)
+	}
+	res, err := c.ShowCommand(ctx, cmd)
 	if err != nil {
-		return APLldp{}, fmt.Errorf("%v", err)
-	}
-	defer res.Body.Close()
-	// b, _ := ioutil.ReadAll(res.Body)
-	// fmt.Println(string(b))
-	var lldp APLldp
-
-	// This Block of Code map[string][]Slice
-	// Is Used Because the Property/Field of the returned
-	// JSON Object is Dynamic/Non-Deterministic, so IT needs
-	// To Be PARSED and Stripped OFF
-	var mlldp map[string][]interface{}
-	if err = json.NewDecoder(res.Body).Decode(&mlldp); err != nil {
-		return APLldp{}, fmt.Errorf("error parsing resp body: %v", err)
-	}
-	for k, lldps := range mlldp {
-		// Ignore These Fields
-		if k == "_data" || k == "_meta" {
+		return APDetails{}, err
+	}
+	d := APDetails{Sections: map[string]map[string]string{}}
+	// the table names are "AP <name> <section>"
+	prefix := detailsPrefix(res.TableNames(), target.Name)
+	if prefix != "" {
+		d.Name = strings.TrimSuffix(strings.TrimPrefix(prefix, "AP "), " ")
+	}
+	for _, name := range res.TableNames() {
+		var rows []struct {
+			Item  string `json:"Item"`
+			Value string `json:"Value"`
+		}
+		if err = res.DecodeTable(name, &rows); err != nil {
+			return APDetails{}, err
+		}
+		if len(rows) == 0 {
 			continue
 		}
-		for _, m := range lldps {
-			v := reflect.ValueOf(m)
-			if v.Kind() == reflect.Map {
-				for _, key := range v.MapKeys() {
-					k := key.String()
-					l := v.MapIndex(key)
-					if l.Interface() == nil {
-						continue
-					}
-					val := l.Interface().(string)
-					switch k {
-					case "AP":
-						lldp.APName = val
-					case "Chassis Name/ID":
-						lldp.RemoteHostname = val
-					case "Mgmt. Address":
-						lldp.RemoteIP = val
-					case "Port ID":
-						lldp.RemoteIntf = val
-					}
-				}
-			}
+		section := map[string]string{}
+		for _, row := range rows {
+			section[row.Item] = row.Value
+		}
+		if prefix != "" {
+			name = strings.TrimPrefix(name, prefix)
+		}
+		d.Sections[name] = section
+		if strings.HasPrefix(name, "Radio ") {
+			d.Radios = append(d.Radios, radioDetails(name, section))
 		}
 	}
-	return lldp, nil
+	if len(d.Sections) == 0 {
+		return APDetails{}, fmt.Errorf("%s: %w", target, ErrApNotFound)
+	}
+	d.PrimaryWlc = d.item("LMS IP Address")
+	d.IPAddr = d.item("AP IP Address")
+	d.Group = d.item("Group")
+	d.Status = d.item("Status")
+	d.Model = d.item("AP Type")
+	d.MacAddr = d.item("Wired MAC Address")
+	d.Serial = d.item("Serial #")
+	d.SecondaryWlc = d.item("Standby IP Address", "Standby IP")
+	d.Uptime = parseUptime(d.item("Up time", "Uptime"))
+	d.BootVersion = d.item("Boot Version")
+	d.SoftwareVersion = d.item("Software Version", "Image Version")
+	d.InstallationMode = d.item("Installation")
+	d.LocationName = d.item("Location name", "Location Name")
+	return d, nil
 }
 
-// RebootAp ...
-func (c *Client) RebootAp(ap AP) (string, error) {
-	if c.cookie == nil {
-		return "", fmt.Errorf(loginWarning)
-	}
-	var apBoot map[string]string
-	switch {
-	case ap.Name != "":
-		apBoot = map[string]string{"ap-name": ap.Name}
-	case ap.MacAddr != "":
-		apBoot = map[string]string{"wired-mac": ap.MacAddr}
+// detailsPrefix returns the "AP <name> " prefix of the tables from "show ap details". Without
+// apName it is the part that all tables have in common, which allows spaces in the AP name.
+// With a single table the name is taken to be one word.
+func detailsPrefix(tables []string, apName string) string {
+	if apName != "" {
+		return "AP " + apName + " "
+	}
+	var names []string
+	for _, name := range tables {
+		if strings.HasPrefix(name, "AP ") {
+			names = append(names, name)
+		}
 	}
-
-	j, _ := json.Marshal(apBoot)
-	body := strings.NewReader(string(j))
-	endpoint := "/configuration/object/apboot"
-	req, err := http.NewRequest("POST", c.BaseURL+endpoint, body)
-	if err != nil {
-		return "", fmt.Errorf("unabled to create request: %v", err)
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		if parts := strings.SplitN(names[0], " ", 3); len(parts) == 3 {
+			return "AP " + parts[1] + " "
+		}
+		return ""
 	}
-	c.updateReq(req, map[string]string{})
-	res, err := c.http.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("request failed: %v", err)
+	prefix := names[0]
+	for _, name := range names[1:] {
+		for !strings.HasPrefix(name, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
 	}
-	defer res.Body.Close()
-	type RebootResult struct {
-		Result struct {
-			Status    int    `json:"status"`
-			StatusStr string `json:"status_str"`
-		} `json:"_global_result"`
+	if i := strings.LastIndex(prefix, " "); i > len("AP") {
+		return prefix[:i+1]
 	}
-	var apReboot RebootResult
-	json.NewDecoder(res.Body).Decode(&apReboot)
-	return strings.ToLower(apReboot.Result.StatusStr), nil
+	return ""
 }
 
-// APAssoc show user-table
-type APAssoc struct {
-	Users []struct {
-		APName        string      `json:"AP name"`
-		AgeDHM        string      `json:"Age(d:h:m)"`
-		Auth          interface{} `json:"Auth"`
-		EssidBssidPhy string      `json:"Essid/Bssid/Phy"`
-		ForwardMode   string      `json:"Forward mode"`
-		HostName      interface{} `json:"Host Name"`
-		IP            string      `json:"IP"`
-		MAC           string      `json:"MAC"`
-		Name          interface{} `json:"Name"`
-		Profile       string      `json:"Profile"`
-		Roaming       string      `json:"Roaming"`
-		Role          string      `json:"Role"`
-		Type          string      `json:"Type"`
-		UserType      string      `json:"User Type"`
-		VPNLink       interface{} `json:"VPN link"`
-	} `json:"Users"`
-}
-
-// Get User (show user-table mac <mac-addr>)
-
-// LED actions that can be taken on AP LEDs
-// var LED = map[string][]string{
-// 	"actions": []string{
-// 		"blink", "normal", "fault-disable", "fault-enable",
-// 	},
-// }
-
-// ApLedActionReq ...
-type ApLedActionReq struct {
-	MacAddr     string `json:"wired-mac"`
-	ApName      string `json:"ap-name"`
-	IPAddr      string `json:"ip-addr"`
-	All         bool   `json:"all"`
-	ApGroup     string `json:"ap-group"`
-	LocalGlobal string `json:"local_global"`
-	Action      string `json:"action_option"`
+// radioDetails builds the radio information from a "Radio N ..." section
+func radioDetails(name string, section map[string]string) APRadioDetails {
+	parts := strings.Fields(name)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	radio := APRadioDetails{
+		Radio:   strings.Join(parts, " "),
+		Band:    section["Band"],
+		Channel: section["Channel"],
+		Mode:    section["Mode"],
+	}
+	if radio.Band == "" {
+		radio.Band = section["Radio Type"]
+	}
+	radio.EIRP, _, _ = parseNumberPrefix(section["EIRP"])
+	return radio
 }
 
-/*
-{
-  "Association Table": [
-    {
-      "Band steer moves (T/S)": "0/0",
-      "Flags": "WVAB",
-      "Name": "ap01.bsc.norton.cafb.al",
-      "aid": "2",
-      "assoc": "y",
-      "assoc. time": "19m:0s",
-      "auth": "y",
-      "bssid": "a8:bd:27:5e:a8:12",
-      "essid": "MyResNet-5G",
-      "l-int": "20",
-      "mac": "88:a4:79:cd:30:47",
-      "num assoc": "1",
-      "phy": "a-VHT-40sgi-2ss",
-      "phy_cap": "a-HE-80-2ss-V",
-      "tunnel-id": "0x10a35",
-      "vlan-id": "1180"
-	},
-*/
-
 // GetAp ...
 func (c *Client) GetAp(apName string) (AP, error) {
-	ap := AP{Name: apName}
-	if c.cookie == nil {
-		return ap, fmt.Errorf(loginWarning)
-	}
-	req, err := c.genGetReq("/configuration/showcommand")
-	if err != nil {
-		return ap, fmt.Errorf(loginWarning)
-	}
-	cmd := fmt.Sprintf("show ap details ap-name %s", apName)
-	qs := map[string]string{"command": cmd}
-	c.updateReq(req, qs)
-	res, err := c.http.Do(req)
-	if err != nil {
-		return ap, fmt.Errorf(loginWarning)
-	}
-	defer res.Body.Close()
-	type resData struct {
-		Item  string `json:"Item"`
-		Value string `json:"Value"`
-	}
-	type resResult map[string][]resData
-	basicKey := fmt.Sprintf("AP %s Basic Information", apName)
-	hwKey := fmt.Sprintf("AP %s Hardware Information", apName)
-	var result resResult
-	json.NewDecoder(res.Body).Decode(&result)
-	for _, val := range result[basicKey] {
-		switch {
-		case val.Item == "LMS IP Address":
-			ap.PrimaryWlc = val.Value
-		case val.Item == "AP IP Address":
-			ap.IPAddr = val.Value
-		case val.Item == "Group":
-			ap.Group = val.Value
-		case val.Item == "Status":
-			ap.Status = val.Value
-		}
-	}
-	for _, val := range result[hwKey] {
-		switch {
-		case val.Item == "AP Type":
-			ap.Model = val.Value
-		case val.Item == "Wired MAC Address":
-			ap.MacAddr = val.Value
-		case val.Item == "Serial #":
-			ap.Serial = val.Value
-		}
-	}
-	return ap, nil
+	return c.GetApContext(context.Background(), apName)
 }
 
-// GetApAssocCount returns the number of Clients Registered with a Specific AP
-// Can only be run on the Controller the AP is Registered with
-func (c *Client) GetApAssocCount(apName string) (int, error) {
-	if c.cookie == nil {
-		return 0, fmt.Errorf(loginWarning)
-	}
-	req, err := c.genGetReq("/configuration/showcommand")
-	if err != nil {
-		return 0, fmt.Errorf(loginWarning)
-	}
-	cmd := fmt.Sprintf("show ap association ap-name %s", apName)
-	qs := map[string]string{"command": cmd}
-	c.updateReq(req, qs)
-	res, err := c.http.Do(req)
+// GetApContext is like GetAp but uses ctx for the request
+func (c *Client) GetApContext(ctx context.Context, apName string) (AP, error) {
+	d, err := c.GetApDetails(ctx, ApTarget{Name: apName})
 	if err != nil {
-		return 0, fmt.Errorf(loginWarning)
-	}
-	defer res.Body.Close()
-	type ApAssoc struct {
-		VlanID string `json:"vlan-id"`
+		return AP{Name: apName}, err
 	}
-	type resResult map[string][]ApAssoc
-	var result resResult
-	json.NewDecoder(res.Body).Decode(&result)
-	return len(result["Association Table"]), nil
+	ap := d.AP
+	ap.Name = apName
+	return ap, nil
 }
@@ -0,0 +1,64 @@
+package arubaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDetailsPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		tables []string
+		apName string
+		want   string
+	}{
+		{"name given", []string{"AP lobby 1 Basic Information"}, "lobby 1", "AP lobby 1 "},
+		{"common prefix", []string{"AP lobby 1 Basic Information", "AP lobby 1 Hardware Information", "AP lobby 1 Radio 0 Operating Information"}, "", "AP lobby 1 "},
+		{"single table", []string{"AP ap01 Basic Information"}, "", "AP ap01 "},
+		{"no AP tables", []string{"Basic Information"}, "", ""},
+		{"none", nil, "", ""},
+	}
+	for _, tt := range tests {
+		if got := detailsPrefix(tt.tables, tt.apName); got != tt.want {
+			t.Errorf("%s: detailsPrefix() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetApDetails(t *testing.T) {
+	c, f := newFakeController(t, func(w http.ResponseWriter, r *http.Request, valid bool) {
+		switch r.URL.Query().Get("command") {
+		case "show ap details ap-name lobby 1", "show ap details wired-mac 00:11:22:33:44:55":
+			fmt.Fprint(w, `{
+				"AP lobby 1 Basic Information":[{"Item":"Group","Value":"g1"},{"Item":"AP IP Address","Value":"10.0.0.5"}],
+				"AP lobby 1 Hardware Information":[{"Item":"AP Type","Value":"535"}],
+				"AP lobby 1 Radio 0 Operating Information":[{"Item":"Channel","Value":"36E"},{"Item":"EIRP","Value":"18.0 dBm"}]}`)
+		default:
+			fmt.Fprint(w, `{"_data":["AP is not registered"]}`)
+		}
+	})
+	defer f.Close()
+	for _, target := range []ApTarget{{Name: "lobby 1"}, {WiredMac: "00:11:22:33:44:55"}} {
+		d, err := c.GetApDetails(context.Background(), target)
+		if err != nil {
+			t.Fatalf("GetApDetails(%s) error = %v", target, err)
+		}
+		if d.Name != "lobby 1" || d.Group != "g1" || d.Model != "535" || d.IPAddr != "10.0.0.5" {
+			t.Errorf("GetApDetails(%s) = %+v", target, d.AP)
+		}
+		if len(d.Radios) != 1 || d.Radios[0].Radio != "Radio 0" || d.Radios[0].EIRP != 18 {
+			t.Errorf("GetApDetails(%s) radios = %+v", target, d.Radios)
+		}
+	}
+	if _, err := c.GetApDetails(context.Background(), ApTarget{Name: "missing"}); !errors.Is(err, ErrApNotFound) {
+		t.Errorf("GetApDetails() of unknown AP error = %v, want %v", err, ErrApNotFound)
+	}
+	for _, target := range []ApTarget{{Name: "lobby 1", Group: "g1"}, {Name: "lobby 1", All: true}, {Group: "g1"}, {}} {
+		if _, err := c.GetApDetails(context.Background(), target); !errors.Is(err, ErrInvalidTarget) {
+			t.Errorf("GetApDetails(%+v) error = %v, want %v", target, err, ErrInvalidTarget)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package arubaos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Intf the Aruba AP Interface Information
+type Intf struct {
+	Duplex    string `json:"Duplex"`
+	MAC       string `json:"MAC"`
+	Oper      string `json:"Oper"`
+	Port      string `json:"Port"`
+	RXBytes   string `json:"RX-Bytes"`
+	RXPackets string `json:"RX-Packets"`
+	Speed     string `json:"Speed"`
+	TXBytes   string `json:"TX-Bytes"`
+	TXPackets string `json:"TX-Packets"`
+}
+
+// GetApPortStatus retrieves Interface statistics of an AP
+// This Command Must be run from a Controller *NOT MM
+// Only the first port that is up is returned, see GetApPorts for all ports
+func (c *Client) GetApPortStatus(mac string) (Intf, error) {
+	return c.GetApPortStatusContext(context.Background(), mac)
+}
+
+// GetApPortStatusContext is like GetApPortStatus but uses ctx for the request
+func (c *Client) GetApPortStatusContext(ctx context.Context, mac string) (Intf, error) {
+	rows, err := c.getApPortRows(ctx, mac)
+	if err != nil {
+		return Intf{}, err
+	}
+	for _, row := range rows {
+		if row.String("Oper") == "up" {
+			return Intf{
+				Duplex:    row.String("Duplex"),
+				MAC:       row.String("MAC"),
+				Oper:      row.String("Oper"),
+				Port:      row.String("Port"),
+				RXBytes:   row.String("RX-Bytes"),
+				RXPackets: row.String("RX-Packets"),
+				Speed:     row.String("Speed"),
+				TXBytes:   row.String("TX-Bytes"),
+				TXPackets: row.String("TX-Packets"),
+			}, nil
+		}
+	}
+	return Intf{}, nil
+}
+
+// getApPortRows returns the rows from "show ap port status"
+func (c *Client) getApPortRows(ctx context.Context, mac string) ([]ShowRow, error) {
+	res, err := c.ShowCommand(ctx, fmt.Sprintf("show ap port status wired-mac %s", mac))
+	if err != nil {
+		return nil, err
+	}
+	// The name of the returned table is
+	// Dynamic/Non-Deterministic, so every
+	// table in the response is PARSED
+	var rows []ShowRow
+	for _, name := range res.TableNames() {
+		table, err := res.Rows(name)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, table...)
+	}
+	return rows, nil
+}
+
+// Duplex the duplex mode of a port
+type Duplex int
+
+// Duplex modes
+const (
+	DuplexUnknown Duplex = iota
+	DuplexHalf
+	DuplexFull
+)
+
+func (d Duplex) String() string {
+	switch d {
+	case DuplexHalf:
+		return "half"
+	case DuplexFull:
+		return "full"
+	}
+	return "unknown"
+}
+
+// parseDuplex parses the Duplex column
+func parseDuplex(s string) Duplex {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "full":
+		return DuplexFull
+	case "half":
+		return DuplexHalf
+	}
+	return DuplexUnknown
+}
+
+// parseSpeedMbps parses the Speed column, e.g. "1 Gb/s", "100Mb/s" or "2.5G", returning 0 if unknown
+func parseSpeedMbps(s string) int {
+	n, unit, ok := parseNumberPrefix(s)
+	if !ok {
+		return 0
+	}
+	if strings.HasPrefix(unit, "g") {
+		n *= 1000
+	}
+	return int(n)
+}
+
+// parseCounter parses a packet or byte counter
+func parseCounter(s string) uint64 {
+	n, _ := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	return n
+}
+
+// ApPort one uplink port on an AP (eth0, eth1, bond0, ...) from "show ap port status"
+type ApPort struct {
+	Port      string
+	MAC       string
+	Oper      string // operational state as reported, e.g. up or down
+	Up        bool
+	SpeedMbps int
+	Duplex    Duplex
+	RXBytes   uint64
+	RXPackets uint64
+	TXBytes   uint64
+	TXPackets uint64
+}
+
+// GetApPorts returns all uplink ports on an AP
+// This Command Must be run from a Controller *NOT MM
+func (c *Client) GetApPorts(ctx context.Context, mac string) ([]ApPort, error) {
+	rows, err := c.getApPortRows(ctx, mac)
+	if err != nil {
+		return nil, err
+	}
+	var ports []ApPort
+	for _, row := range rows {
+		oper := row.String("Oper")
+		ports = append(ports, ApPort{
+			Port:      row.String("Port"),
+			MAC:       row.String("MAC"),
+			Oper:      oper,
+			Up:        oper == "up",
+			SpeedMbps: parseSpeedMbps(row.String("Speed")),
+			Duplex:    parseDuplex(row.String("Duplex")),
+			RXBytes:   parseCounter(row.String("RX-Bytes")),
+			RXPackets: parseCounter(row.String("RX-Packets")),
+			TXBytes:   parseCounter(row.String("TX-Bytes")),
+			TXPackets: parseCounter(row.String("TX-Packets")),
+		})
+	}
+	return ports, nil
+}
+
+// ApPortSnapshot the ports of an AP at a point in time, used to calculate rates
+type ApPortSnapshot struct {
+	Time  time.Time
+	Ports []ApPort
+}
+
+// GetApPortSnapshot returns the ports of an AP together with the time they were read
+func (c *Client) GetApPortSnapshot(ctx context.Context, mac string) (ApPortSnapshot, error) {
+	ports, err := c.GetApPorts(ctx, mac)
+	return ApPortSnapshot{Time: time.Now(), Ports: ports}, err
+}
+
+// PortRate the traffic on a port between two snapshots
+type PortRate struct {
+	Port     string
+	Interval time.Duration
+	RXBps    float64 // bits per second
+	TXBps    float64
+	RXPps    float64 // packets per second
+	TXPps    float64
+}
+
+// PortRates calculates the rate of each port found in both snapshots. Counters that went
+// backwards, e.g. after an AP reboot, give a rate of 0.
+func PortRates(prev, cur ApPortSnapshot) []PortRate {
+	interval := cur.Time.Sub(prev.Time)
+	if interval <= 0 {
+		return nil
+	}
+	old := map[string]ApPort{}
+	for _, p := range prev.Ports {
+		old[p.Port] = p
+	}
+	secs := interval.Seconds()
+	rate := func(from, to uint64) float64 {
+		if to < from {
+			return 0
+		}
+		return float64(to-from) / secs
+	}
+	var rates []PortRate
+	for _, p := range cur.Ports {
+		o, ok := old[p.Port]
+		if !ok {
+			continue
+		}
+		rates = append(rates, PortRate{
+			Port:     p.Port,
+			Interval: interval,
+			RXBps:    rate(o.RXBytes, p.RXBytes) * 8,
+			TXBps:    rate(o.TXBytes, p.TXBytes) * 8,
+			RXPps:    rate(o.RXPackets, p.RXPackets),
+			TXPps:    rate(o.TXPackets, p.TXPackets),
+		})
+	}
+	return rates
+}
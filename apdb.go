@@ -1,11 +1,17 @@
 package arubaos
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 )
 
+// ErrPageRepeated is returned by EachMMApDB when the controller answers with the same page again,
+// e.g. when it does not support offset, instead of walking the same APs forever
+var ErrPageRepeated = errors.New("apdatabase returned the same page twice")
+
 // MMApDB the response when retrieving APs from a Mobility Master
 type MMApDB struct {
 	AP []MMAp `json:"AP Database"`
@@ -26,33 +32,108 @@ type MMAp struct {
 // GetMMApDB the Mobility Master has a unique API Call
 // to retrieve APs from its Database
 func (c *Client) GetMMApDB(f AFilter) ([]MMAp, error) {
-	if c.cookie == nil {
-		return nil, fmt.Errorf(loginWarning)
+	return c.GetMMApDBContext(context.Background(), f)
+}
+
+// GetMMApDBContext is like GetMMApDB but uses ctx for the request
+func (c *Client) GetMMApDBContext(ctx context.Context, f AFilter) ([]MMAp, error) {
+	aps, _, err := c.getMMApDBPage(ctx, f)
+	return aps, err
+}
+
+// mmApDBPageSize the number of APs EachMMApDB asks for when AFilter.Limit is not set
+const mmApDBPageSize = 1000
+
+// EachMMApDB walks the whole AP database on the Mobility Master one page at a time and calls fn
+// for each page. The page size is f.Limit, or 1000 if not set, starting at f.Offset.
+// Walking stops when all APs have been seen, when fn returns an error or with ErrPageRepeated
+// when a page starts with the same AP as the page before it. When the controller reports the
+// total number of APs, pages are read until the total is reached even if the controller
+// returns fewer APs than f.Limit; otherwise a short page is taken as the last page.
+func (c *Client) EachMMApDB(ctx context.Context, f AFilter, fn func(page []MMAp) error) error {
+	if f.Limit <= 0 {
+		f.Limit = mmApDBPageSize
 	}
-	req, err := c.genGetReq("/configuration/object/apdatabase")
-	if err != nil {
-		return nil, err
+	f.Count = 0
+	var first string
+	for {
+		aps, total, err := c.getMMApDBPage(ctx, f)
+		if err != nil {
+			return err
+		}
+		if len(aps) == 0 {
+			if total > 0 && f.Offset < total {
+				return fmt.Errorf("apdatabase returned no APs at offset %d of %d", f.Offset, total)
+			}
+			return nil
+		}
+		if f.Offset > 0 && aps[0].MacAddr == first {
+			return ErrPageRepeated
+		}
+		first = aps[0].MacAddr
+		if err = fn(aps); err != nil {
+			return err
+		}
+		f.Offset += len(aps)
+		if total > 0 {
+			if f.Offset >= total {
+				return nil
+			}
+		} else if len(aps) < f.Limit {
+			return nil
+		}
 	}
+}
+
+// getMMApDBPage returns the APs selected by f and the total number of APs
+// reported in _pagination, 0 if the controller did not send it
+func (c *Client) getMMApDBPage(ctx context.Context, f AFilter) ([]MMAp, int, error) {
 	if f.CfgPath == "" {
 		f.CfgPath = "/md"
 	}
 	// Custom QueryString for Request
-	qs := map[string]string{"config_path": f.CfgPath}
+	qs := map[string]string{}
 	if f.Count != 0 {
 		qs["count"] = strconv.Itoa(f.Count)
 	}
-	// Add Common Values to the REQ
-	c.updateReq(req, qs)
-	res, err := c.http.Do(req)
+	if f.Offset != 0 {
+		qs["offset"] = strconv.Itoa(f.Offset)
+	}
+	if f.Limit != 0 {
+		qs["limit"] = strconv.Itoa(f.Limit)
+	}
+	var filters []ObjectFilter
+	for _, kv := range [][2]string{{"apgroup", f.ApGroup}, {"model", f.Model}, {"status", f.Status}, {"switchip", f.SwitchIP}} {
+		if kv[1] != "" {
+			filters = append(filters, ObjectFilter{Field: "apdatabase." + kv[0], Op: FilterEq, Values: []string{kv[1]}})
+		}
+	}
+	if len(filters) > 0 {
+		filter, err := encodeFilters(filters)
+		if err != nil {
+			return nil, 0, err
+		}
+		qs["filter"] = filter
+	}
+	data, err := c.getObject(ctx, "apdatabase", f.CfgPath, qs)
 	if err != nil {
-		return nil, fmt.Errorf("%v", err)
+		return nil, 0, err
 	}
-	defer res.Body.Close()
 	var apDb MMApDB
-	if err = json.NewDecoder(res.Body).Decode(&apDb); err != nil {
-		return nil, fmt.Errorf("error parsing resp body: %v", err)
+	if raw, ok := data.Raw["AP Database"]; ok {
+		if err = json.Unmarshal(raw, &apDb.AP); err != nil {
+			return nil, 0, &DecodeError{Endpoint: "/configuration/object/apdatabase", Command: "apdatabase", Err: err}
+		}
 	}
-	return apDb.AP, nil
+	var pagination struct {
+		Total flexInt `json:"total"`
+	}
+	if raw, ok := data.Raw["_pagination"]; ok {
+		if err = json.Unmarshal(raw, &pagination); err != nil {
+			return nil, 0, &DecodeError{Endpoint: "/configuration/object/apdatabase", Command: "apdatabase", Err: err}
+		}
+	}
+	return apDb.AP, int(pagination.Total), nil
 }
 
 // APDatabase the response from a show ap database long cmd on a MM/WLC
@@ -76,23 +157,18 @@ type AP struct {
 // GetApDB retrieves AccessPoints associated with a WLC
 // show ap database long
 func (c *Client) GetApDB() ([]AP, error) {
-	if c.cookie == nil {
-		return nil, fmt.Errorf(loginWarning)
-	}
-	req, err := c.genGetReq("/configuration/showcommand")
+	return c.GetApDBContext(context.Background())
+}
+
+// GetApDBContext is like GetApDB but uses ctx for the request
+func (c *Client) GetApDBContext(ctx context.Context) ([]AP, error) {
+	res, err := c.ShowCommand(ctx, "show ap database long")
 	if err != nil {
 		return nil, err
 	}
-	qs := map[string]string{"command": "show ap database long"}
-	c.updateReq(req, qs)
-	res, err := c.http.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("%v", err)
-	}
-	defer res.Body.Close()
-	var apDatabase APDatabase
-	if err = json.NewDecoder(res.Body).Decode(&apDatabase); err != nil {
-		return nil, fmt.Errorf("error parsing resp body: %v", err)
+	var aps []AP
+	if err = res.DecodeTable("AP Database", &aps); err != nil {
+		return nil, err
 	}
-	return apDatabase.AP, nil
+	return aps, nil
 }
@@ -0,0 +1,88 @@
+package arubaos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// apDBHandler serves apdatabase from a list of total APs, at most pageCap per page.
+// With ignoreOffset every request gets the first page, totalField sets the reported total.
+func apDBHandler(total, pageCap int, ignoreOffset bool, totalField string) func(http.ResponseWriter, *http.Request, bool) {
+	return func(w http.ResponseWriter, r *http.Request, valid bool) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if ignoreOffset {
+			offset = 0
+		}
+		if limit == 0 || limit > pageCap {
+			limit = pageCap
+		}
+		aps := []MMAp{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			aps = append(aps, MMAp{MacAddr: fmt.Sprintf("00:00:00:00:%02x:%02x", i/256, i%256)})
+		}
+		resp := map[string]interface{}{"AP Database": aps, "_meta": []string{"apmac"}}
+		if totalField != "" {
+			resp["_pagination"] = json.RawMessage(fmt.Sprintf(`{"offset":%d,"limit":%d,"total":%s}`, offset, limit, totalField))
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestEachMMApDB(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        int
+		pageCap      int
+		ignoreOffset bool
+		totalField   string
+		limit        int
+		want         int
+		wantErr      error
+	}{
+		{name: "pages capped below limit", total: 2500, pageCap: 500, totalField: "2500", limit: 1000, want: 2500},
+		{name: "total as string", total: 2500, pageCap: 500, totalField: `"2500"`, limit: 1000, want: 2500},
+		{name: "full pages", total: 2500, pageCap: 1000, totalField: "2500", want: 2500},
+		{name: "no total, short last page", total: 2500, pageCap: 1000, want: 2500},
+		{name: "no total, exact pages", total: 2000, pageCap: 1000, want: 2000},
+		{name: "offset ignored", total: 2500, pageCap: 1000, ignoreOffset: true, want: 1000, wantErr: ErrPageRepeated},
+		{name: "empty", pageCap: 1000, totalField: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, f := newFakeController(t, apDBHandler(tt.total, tt.pageCap, tt.ignoreOffset, tt.totalField))
+			defer f.Close()
+			seen := map[string]bool{}
+			err := c.EachMMApDB(context.Background(), AFilter{Limit: tt.limit}, func(page []MMAp) error {
+				for _, ap := range page {
+					seen[ap.MacAddr] = true
+				}
+				return nil
+			})
+			if err != tt.wantErr {
+				t.Errorf("EachMMApDB() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(seen) != tt.want {
+				t.Errorf("EachMMApDB() saw %d APs, want %d", len(seen), tt.want)
+			}
+		})
+	}
+}
+
+func TestEachMMApDBStopsOnError(t *testing.T) {
+	c, f := newFakeController(t, apDBHandler(2500, 500, false, "2500"))
+	defer f.Close()
+	stop := fmt.Errorf("stop")
+	pages := 0
+	err := c.EachMMApDB(context.Background(), AFilter{}, func(page []MMAp) error {
+		pages++
+		return stop
+	})
+	if err != stop || pages != 1 {
+		t.Errorf("EachMMApDB() = %v after %d pages, want %v after 1", err, pages, stop)
+	}
+}
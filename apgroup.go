@@ -0,0 +1,120 @@
+package arubaos
+
+import (
+	"context"
+	"fmt"
+)
+
+// RadioProfile the radio profiles that can be attached to an AP group
+type RadioProfile string
+
+// Radio profiles in an AP group
+const (
+	RadioProfile5GHz  RadioProfile = "dot11a_prof"
+	RadioProfile24GHz RadioProfile = "dot11g_prof"
+)
+
+// ApGroup the ap_group configuration object
+type ApGroup struct {
+	Name          string       `json:"profile-name"`
+	VirtualAps    []ProfileRef `json:"virtual_ap,omitempty"`
+	Dot11aProf    *ProfileRef  `json:"dot11a_prof,omitempty"`
+	Dot11gProf    *ProfileRef  `json:"dot11g_prof,omitempty"`
+	RegDomainProf *ProfileRef  `json:"reg_domain_prof,omitempty"`
+	ApSysProf     *ProfileRef  `json:"ap_sys_prof,omitempty"`
+}
+
+// ListApGroups returns all AP groups at configPath
+func (c *Client) ListApGroups(ctx context.Context, configPath string) ([]ApGroup, error) {
+	return c.getApGroups(ctx, configPath, nil)
+}
+
+// GetApGroup returns the AP group called name at configPath, ErrObjectNotFound if it does not exist
+func (c *Client) GetApGroup(ctx context.Context, configPath, name string) (ApGroup, error) {
+	groups, err := c.getApGroups(ctx, configPath, []ObjectFilter{{Field: "ap_group.profile-name", Op: FilterEq, Values: []string{name}}})
+	if err != nil {
+		return ApGroup{}, err
+	}
+	for _, group := range groups {
+		if group.Name == name {
+			return group, nil
+		}
+	}
+	return ApGroup{}, fmt.Errorf("ap_group %s: %w", name, ErrObjectNotFound)
+}
+
+// getApGroups reads ap_group objects
+func (c *Client) getApGroups(ctx context.Context, configPath string, filters []ObjectFilter) ([]ApGroup, error) {
+	data, err := c.GetObject(ctx, "ap_group", configPath, filters)
+	if err != nil {
+		return nil, err
+	}
+	var groups []ApGroup
+	err = data.Decode(&groups)
+	return groups, err
+}
+
+// CreateApGroup creates group at configPath, or updates it if it already exists
+func (c *Client) CreateApGroup(ctx context.Context, configPath string, group ApGroup) error {
+	_, err := c.PostObjects(ctx, configPath, []ObjectAction{{Name: "ap_group", Data: group}})
+	return err
+}
+
+// DeleteApGroup deletes the AP group called name at configPath
+func (c *Client) DeleteApGroup(ctx context.Context, configPath, name string) error {
+	action := ObjectAction{Name: "ap_group", Action: ActionDelete, Data: ApGroup{Name: name}}
+	_, err := c.PostObjects(ctx, configPath, []ObjectAction{action})
+	return err
+}
+
+// updateApGroup writes the changes in group to an AP group that must already exist,
+// so a misspelled group name gives ErrObjectNotFound instead of a new group
+func (c *Client) updateApGroup(ctx context.Context, configPath string, group ApGroup) error {
+	if _, err := c.GetApGroup(ctx, configPath, group.Name); err != nil {
+		return err
+	}
+	return c.CreateApGroup(ctx, configPath, group)
+}
+
+// AttachVirtualAp adds the virtual AP profile vap to the AP group, ErrObjectNotFound if the group does not exist
+func (c *Client) AttachVirtualAp(ctx context.Context, configPath, group, vap string) error {
+	return c.updateApGroup(ctx, configPath, ApGroup{Name: group, VirtualAps: []ProfileRef{{Name: vap}}})
+}
+
+// DetachVirtualAp removes the virtual AP profile vap from the AP group, ErrObjectNotFound if the group does not exist
+func (c *Client) DetachVirtualAp(ctx context.Context, configPath, group, vap string) error {
+	return c.updateApGroup(ctx, configPath, ApGroup{Name: group, VirtualAps: []ProfileRef{{Name: vap, Action: ActionDelete}}})
+}
+
+// AttachRadioProfile sets the radio profile for one band in the AP group, ErrObjectNotFound if the group does not exist
+func (c *Client) AttachRadioProfile(ctx context.Context, configPath, group string, radio RadioProfile, profile string) error {
+	g, err := radioGroup(group, radio, ProfileRef{Name: profile})
+	if err != nil {
+		return err
+	}
+	return c.updateApGroup(ctx, configPath, g)
+}
+
+// DetachRadioProfile removes the radio profile for one band from the AP group, the band goes back to the default profile.
+// ErrObjectNotFound is returned if the group does not exist.
+func (c *Client) DetachRadioProfile(ctx context.Context, configPath, group string, radio RadioProfile, profile string) error {
+	g, err := radioGroup(group, radio, ProfileRef{Name: profile, Action: ActionDelete})
+	if err != nil {
+		return err
+	}
+	return c.updateApGroup(ctx, configPath, g)
+}
+
+// radioGroup returns an ApGroup with ref set for radio
+func radioGroup(group string, radio RadioProfile, ref ProfileRef) (ApGroup, error) {
+	g := ApGroup{Name: group}
+	switch radio {
+	case RadioProfile5GHz:
+		g.Dot11aProf = &ref
+	case RadioProfile24GHz:
+		g.Dot11gProf = &ref
+	default:
+		return g, fmt.Errorf("unknown radio profile %q", radio)
+	}
+	return g, nil
+}
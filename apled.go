@@ -0,0 +1,89 @@
+package arubaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LedAction actions that can be taken on AP LEDs
+type LedAction string
+
+// LED actions supported by ArubaOS
+const (
+	LedBlink        LedAction = "blink"
+	LedNormal       LedAction = "normal"
+	LedFaultDisable LedAction = "fault-disable"
+	LedFaultEnable  LedAction = "fault-enable"
+)
+
+// ErrInvalidLedAction is returned by SetApLed for unknown actions
+var ErrInvalidLedAction = errors.New("invalid LED action")
+
+// ApLedActionReq selects the APs to change the LEDs on, set exactly one of
+// MacAddr, ApName, IPAddr, ApGroup or All
+type ApLedActionReq struct {
+	MacAddr     string    `json:"wired-mac,omitempty"`
+	ApName      string    `json:"ap-name,omitempty"`
+	IPAddr      string    `json:"ip-addr,omitempty"`
+	All         bool      `json:"all,omitempty"`
+	ApGroup     string    `json:"ap-group,omitempty"`
+	LocalGlobal string    `json:"local_global,omitempty"`
+	Action      LedAction `json:"action_option"`
+}
+
+// target returns the APs selected in r as an ApTarget
+func (r ApLedActionReq) target() ApTarget {
+	return ApTarget{WiredMac: r.MacAddr, Name: r.ApName, IPAddr: r.IPAddr, Group: r.ApGroup, All: r.All}
+}
+
+// validate checks the action and that exactly one target is set
+func (r ApLedActionReq) validate() error {
+	switch r.Action {
+	case LedBlink, LedNormal, LedFaultDisable, LedFaultEnable:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidLedAction, r.Action)
+	}
+	return r.target().validate()
+}
+
+// SetApLed performs an LED action on the APs selected in req
+func (c *Client) SetApLed(ctx context.Context, req ApLedActionReq) error {
+	if !c.loggedIn() {
+		return ErrNotLoggedIn
+	}
+	if err := req.validate(); err != nil {
+		return err
+	}
+	_, err := c.postObject(ctx, "/configuration/object/ap_leds", map[string]string{}, req)
+	return err
+}
+
+// ledRestoreTimeout how long BlinkApLed waits for the LEDs to be set back to normal
+// when ctx is cancelled while blinking
+const ledRestoreTimeout = 30 * time.Second
+
+// BlinkApLed blinks the LEDs on the APs selected in req for d and then sets them back to normal.
+// If ctx is cancelled while blinking the LEDs are still set back to normal.
+func (c *Client) BlinkApLed(ctx context.Context, req ApLedActionReq, d time.Duration) error {
+	req.Action = LedBlink
+	if err := c.SetApLed(ctx, req); err != nil {
+		return err
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	restoreCtx := ctx
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		var cancel context.CancelFunc
+		restoreCtx, cancel = context.WithTimeout(context.Background(), ledRestoreTimeout)
+		defer cancel()
+	}
+	req.Action = LedNormal
+	if err := c.SetApLed(restoreCtx, req); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
@@ -1,10 +1,7 @@
 package arubaos
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"strings"
+	"context"
 )
 
 // ApProv the Type Needed to by the ProvAPs Method
@@ -25,9 +22,15 @@ type ApProv struct {
 
 // ProvAPs provisions the AP Name and AP Group
 // This can only be performed using the MM
+// APs that could not be renamed or regrouped are reported in an *ObjectError
 func (c *Client) ProvAPs(newAPs []ApProv) error {
-	if c.cookie == nil {
-		return fmt.Errorf(loginWarning)
+	return c.ProvAPsContext(context.Background(), newAPs)
+}
+
+// ProvAPsContext is like ProvAPs but uses ctx for the request
+func (c *Client) ProvAPsContext(ctx context.Context, newAPs []ApProv) error {
+	if !c.loggedIn() {
+		return ErrNotLoggedIn
 	}
 	type apRenameReq struct {
 		MacAddr string `json:"wired-mac"`
@@ -60,22 +63,6 @@ func (c *Client) ProvAPs(newAPs []ApProv) error {
 	}
 	apProv := apProvision{APConfList: apConf}
 
-	jdata, _ := json.Marshal(apProv)
-	body := strings.NewReader(string(jdata))
-
-	endpoint := "/configuration/object"
-	req, err := http.NewRequest(http.MethodPost, c.BaseURL+endpoint, body)
-	if err != nil {
-		return err
-	}
-
-	// Set Appropriate Values needed for the Req to Succeed
-	c.updateReq(req, map[string]string{})
-
-	res, err := c.http.Do(req)
-	_ = res.Body.Close()
-	if err != nil {
-		return fmt.Errorf("%v", err)
-	}
-	return nil
+	_, err := c.postConfig(ctx, "/configuration/object", map[string]string{}, apProv)
+	return err
 }
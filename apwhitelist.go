@@ -1,39 +1,58 @@
 package arubaos
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 )
 
+// CpSecAction the action for a whitelist entry in CpSecModify
+type CpSecAction string
+
+const (
+	CpSecApprovedReadyForCert CpSecAction = "approved-ready-for-cert"
+	CpSecCertifiedFactoryCert CpSecAction = "certified-factory-cert"
+	CpSecRevoke               CpSecAction = "revoke" // set RevokeTxt with the reason
+)
+
+// CpSecCertType the certificate type of a whitelist entry
+type CpSecCertType string
+
+const (
+	CpSecFactoryCert CpSecCertType = "factory-cert"
+	CpSecSwitchCert  CpSecCertType = "switch-cert"
+)
+
+// CpSecMode enables or disables a whitelist entry
+type CpSecMode string
+
+const (
+	CpSecEnable  CpSecMode = "enable"
+	CpSecDisable CpSecMode = "disable"
+)
+
 // WdbCpSec whitelist properties
 type WdbCpSec struct {
 	// optional use only for Modify
-	// true
+	// set by CpSecModify when Act is set
 	State bool `json:"state,omitempty"`
 	// optional use only for Modify
-	// approved-ready-for-cert
-	// certified-factory-cert
-	Act string `json:"act,omitempty"`
+	Act CpSecAction `json:"act,omitempty"`
 	// optional use only for Modify
 	RevokeTxt string `json:"revoke-text,omitempty"`
 	// optional
 	Description string `json:"description,omitempty"`
 	// optional use only for Modify
-	// true
+	// set by CpSecModify when CertType is set
 	Cert bool `json:"cert-type,omitempty"`
 	// optional use only for Modify
-	// factory-cert
-	// switch-cert
-	CertType string `json:"certtype,omitempty"`
+	CertType CpSecCertType `json:"certtype,omitempty"`
 	// optional use only for Modify
-	// true
+	// set by CpSecModify when ModeAct is set
 	Mode bool `json:"mode,omitempty"`
 	// optional use only for Modify
-	// enable|disable
-	ModeAct string `json:"modeact,omitempty"`
+	ModeAct CpSecMode `json:"modeact,omitempty"`
 	// Do not use for DEL
 	ApName string `json:"ap_name,omitempty"`
 	// Do not use for DEL
@@ -43,9 +62,15 @@ type WdbCpSec struct {
 }
 
 // CpSecAdd add APs to Whitelist
+// Entries that ArubaOS rejects are reported in an *ObjectError
 func (c *Client) CpSecAdd(aps []WdbCpSec) error {
-	if c.cookie == nil {
-		return fmt.Errorf(loginWarning)
+	return c.CpSecAddContext(context.Background(), aps)
+}
+
+// CpSecAddContext is like CpSecAdd but uses ctx for the request
+func (c *Client) CpSecAddContext(ctx context.Context, aps []WdbCpSec) error {
+	if !c.loggedIn() {
+		return ErrNotLoggedIn
 	}
 	type addWhitelist struct {
 		CpSecAdd WdbCpSec `json:"wdb_cpsec_add_mac"`
@@ -58,60 +83,46 @@ func (c *Client) CpSecAdd(aps []WdbCpSec) error {
 		ApConfList []addWhitelist `json:"_list"`
 	}
 	apWhitelist := apAddWl{ApConfList: apList}
-	j, _ := json.Marshal(apWhitelist)
-	fmt.Println(string(j))
-	body := strings.NewReader(string(j))
-
-	endpoint := "/configuration/object"
-	req, err := http.NewRequest(http.MethodPost, c.BaseURL+endpoint, body)
-
-	c.updateReq(req, map[string]string{})
-	res, err := c.http.Do(req)
-	if err != nil {
-		return fmt.Errorf("%v", err)
-	}
-	defer res.Body.Close()
-	result, err := io.ReadAll(res.Body)
-	fmt.Println(string(result)) // TODO: why print the result, do we need it?
+	_, err := c.postObject(ctx, "/configuration/object", map[string]string{}, apWhitelist)
 	return err
 }
 
+// WithFactoryCert returns a copy of w that certifies the AP with its factory certificate and
+// enables it, which is what CpSecModify used to do for every entry
+func (w WdbCpSec) WithFactoryCert() WdbCpSec {
+	w.Act = CpSecCertifiedFactoryCert
+	w.CertType = CpSecFactoryCert
+	w.ModeAct = CpSecEnable
+	return w
+}
+
 // CpSecModify update APs in Whitelist
+// Each entry is sent as it is, use WithFactoryCert to certify and enable the AP.
+// Entries that ArubaOS rejects are reported in an *ObjectError
 func (c *Client) CpSecModify(aps []WdbCpSec) error {
-	if c.cookie == nil {
-		return fmt.Errorf(loginWarning)
+	return c.CpSecModifyContext(context.Background(), aps)
+}
+
+// CpSecModifyContext is like CpSecModify but uses ctx for the request
+func (c *Client) CpSecModifyContext(ctx context.Context, aps []WdbCpSec) error {
+	if !c.loggedIn() {
+		return ErrNotLoggedIn
 	}
 	type modWl struct {
 		CpSecMod WdbCpSec `json:"wdb_cpsec_modify_mac"`
 	}
 	var modAp []modWl
 	for _, ap := range aps {
-		ap.Act = "certified-factory-cert"
-		ap.Cert = true
-		ap.CertType = "factory-cert"
-		ap.Mode = true
-		ap.ModeAct = "enable"
+		ap.State = ap.Act != ""
+		ap.Cert = ap.CertType != ""
+		ap.Mode = ap.ModeAct != ""
 		modAp = append(modAp, modWl{CpSecMod: ap})
 	}
 	type apModWl struct {
 		ApConfList []modWl `json:"_list"`
 	}
 	apModWhitelist := apModWl{ApConfList: modAp}
-	j, _ := json.Marshal(apModWhitelist)
-	body := strings.NewReader(string(j))
-	endpoint := "/configuration/object"
-	req, err := http.NewRequest(http.MethodPost, c.BaseURL+endpoint, body)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
-	}
-	c.updateReq(req, map[string]string{})
-	res, err := c.http.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %v", err)
-	}
-	defer res.Body.Close()
-	result, err := io.ReadAll(res.Body)
-	fmt.Println(string(result)) // TODO: why print this result?
+	_, err := c.postObject(ctx, "/configuration/object", map[string]string{}, apModWhitelist)
 	return err
 }
 
@@ -124,9 +135,15 @@ func (c *Client) CpSecModify(aps []WdbCpSec) error {
 */
 
 // CpSecDel remove APs from Whitelist
+// Entries that ArubaOS rejects are reported in an *ObjectError
 func (c *Client) CpSecDel(aps []WdbCpSec) error {
-	if c.cookie == nil {
-		return fmt.Errorf(loginWarning)
+	return c.CpSecDelContext(context.Background(), aps)
+}
+
+// CpSecDelContext is like CpSecDel but uses ctx for the request
+func (c *Client) CpSecDelContext(ctx context.Context, aps []WdbCpSec) error {
+	if !c.loggedIn() {
+		return ErrNotLoggedIn
 	}
 	// DelWhitelist ...
 	type delWhitelist struct {
@@ -140,22 +157,218 @@ func (c *Client) CpSecDel(aps []WdbCpSec) error {
 		ApConfList []delWhitelist `json:"_list"`
 	}
 	apDel := apDelWl{ApConfList: apList}
-	j, _ := json.Marshal(apDel)
-	body := strings.NewReader(string(j))
+	_, err := c.postObject(ctx, "/configuration/object", map[string]string{}, apDel)
+	return err
+}
 
-	endpoint := "/configuration/object"
-	req, err := http.NewRequest(http.MethodPost, c.BaseURL+endpoint, body)
+// ClrGapResult the outcome of clearing one AP from the AP database
+type ClrGapResult struct {
+	Target  ApTarget // the target that selected the AP
+	AP      AP       // the entry in the AP database, empty if no AP matched
+	Cleared bool     // false on errors and in dry-run mode
+	Err     error
+}
+
+// ClrGapAp deletes APs from LMS(Controller) Database
+// Each target is looked up in "show ap database long" and every matching AP is cleared by
+// its wired MAC, giving one result per AP. Targets without a match get a result with
+// ErrApNotFound. With dryRun the matching APs are returned without clearing anything.
+// Targets must select APs by WiredMac, Name or Group, All and IPAddr are rejected.
+func (c *Client) ClrGapAp(targets []ApTarget, dryRun bool) ([]ClrGapResult, error) {
+	return c.ClrGapApContext(context.Background(), targets, dryRun)
+}
 
-	c.updateReq(req, map[string]string{})
-	res, err := c.http.Do(req)
+// ClrGapApContext is like ClrGapAp but uses ctx for the request
+func (c *Client) ClrGapApContext(ctx context.Context, targets []ApTarget, dryRun bool) ([]ClrGapResult, error) {
+	for _, target := range targets {
+		if err := target.validate(); err != nil {
+			return nil, err
+		}
+		if target.All || target.IPAddr != "" {
+			return nil, fmt.Errorf("%w: ClrGapAp clears by wired-mac, ap-name or ap-group only", ErrInvalidTarget)
+		}
+	}
+	db, err := c.GetApDBContext(ctx)
 	if err != nil {
-		return fmt.Errorf("%v", err)
+		return nil, err
 	}
-	defer res.Body.Close()
-	result, err := io.ReadAll(res.Body)
-	fmt.Println(string(result)) // TODO: why print out this result, can it be omitted?
-	return err
+	var results []ClrGapResult
+	for _, target := range targets {
+		aps := target.resolve(db)
+		if len(aps) == 0 {
+			results = append(results, ClrGapResult{Target: target, Err: ErrApNotFound})
+			continue
+		}
+		for _, ap := range aps {
+			result := ClrGapResult{Target: target, AP: ap}
+			if !dryRun {
+				clear := map[string]string{"wired-mac": ap.MacAddr}
+				_, result.Err = c.postObject(ctx, "/configuration/object/clear_gap_db", map[string]string{}, clear)
+				result.Cleared = result.Err == nil
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
 }
 
-// ClrGapAp deletes APs from LMS(Controller) Database
-func (c *Client) ClrGapAp() {}
+// CpSecEntry an entry in the control plane security whitelist, from "show whitelist-db cpsec"
+type CpSecEntry struct {
+	MAC         string
+	Enabled     bool
+	State       string // e.g. approved-ready-for-cert, certified-factory-cert, unapproved-factory-cert
+	CertType    string // factory-cert or switch-cert
+	ApName      string
+	ApGroup     string
+	Description string
+	RevokeText  string
+}
+
+// ListCpSec returns the control plane security whitelist
+func (c *Client) ListCpSec(ctx context.Context) ([]CpSecEntry, error) {
+	res, err := c.ShowCommand(ctx, "show whitelist-db cpsec")
+	if err != nil {
+		return nil, err
+	}
+	var entries []CpSecEntry
+	for _, name := range res.TableNames() {
+		rows, err := res.Rows(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			mac := row.String("MAC-Address", "MAC Address", "Name")
+			if mac == "" {
+				continue
+			}
+			entries = append(entries, CpSecEntry{
+				MAC:         mac,
+				Enabled:     strings.EqualFold(row.String("Enable", "Enabled"), "enabled"),
+				State:       row.String("State"),
+				CertType:    row.String("Cert-Type", "Cert Type"),
+				ApName:      row.String("AP-Name", "AP Name"),
+				ApGroup:     row.String("AP-Group", "AP Group"),
+				Description: row.String("Description"),
+				RevokeText:  row.String("Revoke Text", "Revoke-Text"),
+			})
+		}
+	}
+	return entries, nil
+}
+
+// ErrEmptyDesired is returned by ReconcileCpSec when pruning to an empty desired set,
+// which would delete the whole whitelist
+var ErrEmptyDesired = errors.New("refusing to prune the whitelist to an empty desired set")
+
+// CpSecPlan the changes needed to bring the whitelist to the desired state
+type CpSecPlan struct {
+	Add    []WdbCpSec
+	Modify []WdbCpSec
+	Delete []WdbCpSec
+}
+
+// PlanCpSec compares the whitelist entries in current with desired. Entries are matched by MAC,
+// an entry is modified when any field set in desired differs, and only the fields set in desired
+// are sent. New entries that set Act, CertType or ModeAct are added and then modified.
+// Entries that are not in desired are only deleted with prune.
+func PlanCpSec(current []CpSecEntry, desired []WdbCpSec, prune bool) CpSecPlan {
+	var plan CpSecPlan
+	existing := map[string]CpSecEntry{}
+	for _, e := range current {
+		existing[strings.ToLower(e.MAC)] = e
+	}
+	wanted := map[string]bool{}
+	for _, d := range desired {
+		mac := strings.ToLower(d.Name)
+		wanted[mac] = true
+		e, found := existing[mac]
+		switch {
+		case !found:
+			plan.Add = append(plan.Add, WdbCpSec{Name: d.Name, Description: d.Description, ApName: d.ApName, ApGroup: d.ApGroup})
+			if d.Act != "" || d.CertType != "" || d.ModeAct != "" {
+				plan.Modify = append(plan.Modify, d)
+			}
+		case cpSecDiffers(d, e):
+			plan.Modify = append(plan.Modify, d)
+		}
+	}
+	if !prune {
+		return plan
+	}
+	for _, e := range current {
+		if !wanted[strings.ToLower(e.MAC)] {
+			plan.Delete = append(plan.Delete, WdbCpSec{Name: e.MAC})
+		}
+	}
+	return plan
+}
+
+// cpSecDiffers returns true if any field set in d has another value in e
+func cpSecDiffers(d WdbCpSec, e CpSecEntry) bool {
+	switch {
+	case d.ApName != "" && d.ApName != e.ApName,
+		d.ApGroup != "" && d.ApGroup != e.ApGroup,
+		d.Description != "" && d.Description != e.Description,
+		d.Act != "" && !d.Act.matches(e.State),
+		d.CertType != "" && !strings.EqualFold(string(d.CertType), e.CertType),
+		d.ModeAct != "" && (d.ModeAct == CpSecEnable) != e.Enabled:
+		return true
+	}
+	return false
+}
+
+// matches returns true if state is the state of an entry after a is applied
+func (a CpSecAction) matches(state string) bool {
+	if a == CpSecRevoke {
+		return strings.HasPrefix(strings.ToLower(state), "revoke")
+	}
+	return strings.EqualFold(string(a), state)
+}
+
+// CpSecReconcileOptions controls ReconcileCpSec
+type CpSecReconcileOptions struct {
+	// BatchSize the number of entries sent in one request, 0 sends each kind of change in one request
+	BatchSize int
+	// Prune deletes the entries that are not in desired
+	Prune bool
+}
+
+// ReconcileCpSec makes the whitelist match desired. Entries are added and modified first and,
+// with opts.Prune, entries that are not in desired are deleted last. Pruning to an empty desired
+// set returns ErrEmptyDesired. Modified entries are not certified unless Act is set.
+// The plan is returned also on errors, changes after the failing batch are not applied.
+func (c *Client) ReconcileCpSec(ctx context.Context, desired []WdbCpSec, opts CpSecReconcileOptions) (CpSecPlan, error) {
+	if opts.Prune && len(desired) == 0 {
+		return CpSecPlan{}, ErrEmptyDesired
+	}
+	current, err := c.ListCpSec(ctx)
+	if err != nil {
+		return CpSecPlan{}, err
+	}
+	plan := PlanCpSec(current, desired, opts.Prune)
+	if err = cpSecBatches(ctx, plan.Add, opts.BatchSize, c.CpSecAddContext); err != nil {
+		return plan, err
+	}
+	if err = cpSecBatches(ctx, plan.Modify, opts.BatchSize, c.CpSecModifyContext); err != nil {
+		return plan, err
+	}
+	err = cpSecBatches(ctx, plan.Delete, opts.BatchSize, c.CpSecDelContext)
+	return plan, err
+}
+
+// cpSecBatches calls apply with entries split in batches of batchSize
+func cpSecBatches(ctx context.Context, entries []WdbCpSec, batchSize int, apply func(context.Context, []WdbCpSec) error) error {
+	if batchSize <= 0 {
+		batchSize = len(entries)
+	}
+	for start := 0; start < len(entries); start += batchSize {
+		end := start + batchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		if err := apply(ctx, entries[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
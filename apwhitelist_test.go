@@ -0,0 +1,146 @@
+package arubaos
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPlanCpSec(t *testing.T) {
+	current := []CpSecEntry{
+		{MAC: "AA:BB:CC:00:00:01", State: "certified-factory-cert", CertType: "factory-cert", Enabled: true, ApName: "ap01", ApGroup: "g1"},
+		{MAC: "aa:bb:cc:00:00:02", State: "unapproved-factory-cert", Enabled: true, ApName: "ap02", ApGroup: "g1"},
+	}
+	tests := []struct {
+		name    string
+		current []CpSecEntry
+		desired []WdbCpSec
+		prune   bool
+		want    CpSecPlan
+	}{
+		{
+			name:    "no changes",
+			current: current,
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:01", ApName: "ap01"}, {Name: "aa:bb:cc:00:00:02"}},
+			prune:   true,
+		},
+		{
+			name:    "mac is matched without case",
+			current: current,
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:01", ApGroup: "g1"}, {Name: "AA:BB:CC:00:00:02", ApName: "ap02"}},
+			prune:   true,
+		},
+		{
+			name:    "add",
+			current: current,
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:03", ApName: "ap03", ApGroup: "g2"}},
+			want:    CpSecPlan{Add: []WdbCpSec{{Name: "aa:bb:cc:00:00:03", ApName: "ap03", ApGroup: "g2"}}},
+		},
+		{
+			name:    "add with action is modified after the add",
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:03", Act: CpSecApprovedReadyForCert}},
+			want: CpSecPlan{
+				Add:    []WdbCpSec{{Name: "aa:bb:cc:00:00:03"}},
+				Modify: []WdbCpSec{{Name: "aa:bb:cc:00:00:03", Act: CpSecApprovedReadyForCert}},
+			},
+		},
+		{
+			name:    "rename only sends the name",
+			current: current,
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:02", ApName: "ap02-new"}},
+			want:    CpSecPlan{Modify: []WdbCpSec{{Name: "aa:bb:cc:00:00:02", ApName: "ap02-new"}}},
+		},
+		{
+			name:    "approve",
+			current: current,
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:02", Act: CpSecApprovedReadyForCert}},
+			want:    CpSecPlan{Modify: []WdbCpSec{{Name: "aa:bb:cc:00:00:02", Act: CpSecApprovedReadyForCert}}},
+		},
+		{
+			name:    "revoke",
+			current: current,
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:01", Act: CpSecRevoke, RevokeTxt: "stolen"}},
+			want:    CpSecPlan{Modify: []WdbCpSec{{Name: "aa:bb:cc:00:00:01", Act: CpSecRevoke, RevokeTxt: "stolen"}}},
+		},
+		{
+			name:    "already revoked",
+			current: []CpSecEntry{{MAC: "aa:bb:cc:00:00:01", State: "revoked"}},
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:01", Act: CpSecRevoke}},
+		},
+		{
+			name:    "cert type and mode",
+			current: current,
+			desired: []WdbCpSec{
+				{Name: "aa:bb:cc:00:00:01", CertType: CpSecSwitchCert},
+				{Name: "aa:bb:cc:00:00:02", ModeAct: CpSecDisable},
+			},
+			want: CpSecPlan{Modify: []WdbCpSec{
+				{Name: "aa:bb:cc:00:00:01", CertType: CpSecSwitchCert},
+				{Name: "aa:bb:cc:00:00:02", ModeAct: CpSecDisable},
+			}},
+		},
+		{
+			name:    "delete with prune",
+			current: current,
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:01"}},
+			prune:   true,
+			want:    CpSecPlan{Delete: []WdbCpSec{{Name: "aa:bb:cc:00:00:02"}}},
+		},
+		{
+			name:    "no delete without prune",
+			current: current,
+			desired: []WdbCpSec{{Name: "aa:bb:cc:00:00:01"}},
+		},
+		{
+			name:    "empty desired without prune",
+			current: current,
+		},
+		{
+			name:    "empty desired with prune",
+			current: current,
+			prune:   true,
+			want:    CpSecPlan{Delete: []WdbCpSec{{Name: "AA:BB:CC:00:00:01"}, {Name: "aa:bb:cc:00:00:02"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlanCpSec(tt.current, tt.desired, tt.prune)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanCpSec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileCpSecEmptyDesired(t *testing.T) {
+	c := &Client{}
+	if _, err := c.ReconcileCpSec(context.Background(), nil, CpSecReconcileOptions{Prune: true}); err != ErrEmptyDesired {
+		t.Errorf("ReconcileCpSec() error = %v, want %v", err, ErrEmptyDesired)
+	}
+}
+
+func TestCpSecModifySendsEntriesAsGiven(t *testing.T) {
+	var body string
+	c, f := newFakeController(t, func(w http.ResponseWriter, r *http.Request, valid bool) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		fmt.Fprint(w, `{"_global_result":{"status":0,"status_str":"Success"}}`)
+	})
+	defer f.Close()
+	aps := []WdbCpSec{
+		{Name: "aa:bb:cc:00:00:01", Description: "lobby"},
+		WdbCpSec{Name: "aa:bb:cc:00:00:02"}.WithFactoryCert(),
+	}
+	if err := c.CpSecModify(aps); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"_list":[` +
+		`{"wdb_cpsec_modify_mac":{"description":"lobby","name":"aa:bb:cc:00:00:01"}},` +
+		`{"wdb_cpsec_modify_mac":{"state":true,"act":"certified-factory-cert","cert-type":true,"certtype":"factory-cert","mode":true,"modeact":"enable","name":"aa:bb:cc:00:00:02"}}]}`
+	if body != want {
+		t.Errorf("CpSecModify() sent %s, want %s", body, want)
+	}
+}
@@ -1,18 +1,19 @@
 package arubaos
 
 import (
+	"context"
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-const loginWarning string = "you must first login to perform this action"
-
 // Client struct used for the Connection
 // To an Aruba MM and/or Controller
 type Client struct {
@@ -20,8 +21,25 @@ type Client struct {
 	Username string
 	Password string
 	IP       string
+	// OnReauth is called each time the Client logs in again because the
+	// controller reported an expired or invalid session, err is the result
+	// of that login
+	OnReauth func(err error)
+	// AutoCommit makes every successful configuration write call WriteMemory
+	// on the same config_path, so changes are not lost on reload
+	AutoCommit bool
+	// Logger receives debug logs of every request and response, nil disables logging
+	Logger Logger
+	// BeforeRequest is called before each request is sent, including logins and replays after
+	// an expired session. The returned context is used for the request and passed to
+	// AfterResponse, e.g. to carry a tracing span. Return ctx to keep it unchanged.
+	BeforeRequest func(ctx context.Context, info RequestInfo) context.Context
+	// AfterResponse is called when a request has completed or failed
+	AfterResponse func(ctx context.Context, info ResponseInfo)
 
 	http     *http.Client
+	mu       sync.RWMutex // protects cookie and uidAruba
+	reauthMu sync.Mutex   // serializes logins after an expired session
 	cookie   *http.Cookie
 	uidAruba string
 }
@@ -55,78 +73,112 @@ type ArubaAuthResp struct {
 
 // Login establishes a session with an Aruba Device
 func (c *Client) Login() error {
+	return c.LoginContext(context.Background())
+}
+
+// LoginContext is like Login but uses ctx for the request
+func (c *Client) LoginContext(ctx context.Context) error {
 	data := url.Values{}
 	data.Set("username", c.Username)
 	data.Set("password", c.Password)
 	body := strings.NewReader(data.Encode())
-	req, err := http.NewRequest(http.MethodPost, c.BaseURL+"/api/login", body)
+	req, err := c.genPostReq(ctx, "/api/login", body)
 	if err != nil {
-		return fmt.Errorf("failed to create a new request: %v", err)
+		return fmt.Errorf("failed to create a new request: %w", err)
 	}
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	res, err := c.http.Do(req)
+	res, resp, err := c.send(req)
 	if err != nil {
-		return fmt.Errorf("failed to login: %v", err)
+		return err
 	}
-	defer res.Body.Close()
 
 	var authObj ArubaAuthResp
-	json.NewDecoder(res.Body).Decode(&authObj)
+	if err = c.decode(req, resp, &authObj); err != nil {
+		return err
+	}
 	if authObj.GlobalRes.Status != "0" {
-		return fmt.Errorf(authObj.GlobalRes.StatusStr)
+		status, _ := strconv.Atoi(authObj.GlobalRes.Status)
+		return &APIError{
+			HTTPStatus: res.StatusCode,
+			Status:     status,
+			StatusStr:  authObj.GlobalRes.StatusStr,
+			Endpoint:   c.endpoint(req),
+		}
 	}
 	// if we've logged in successfully we'll be able to
 	// grab the AUTH Token AND AuthCookie from the Resp
-	c.uidAruba = authObj.GlobalRes.UIDAruba
-	c.cookie = res.Cookies()[0]
+	cookies := res.Cookies()
+	if len(cookies) == 0 {
+		return errors.New("failed to login: no session cookie in response")
+	}
+	c.setSession(cookies[0], authObj.GlobalRes.UIDAruba)
 	return nil
 }
 
 // Logout of the Controller
 func (c *Client) Logout() (ArubaAuthResp, error) {
-	req, err := c.genGetReq("/api/logout")
+	return c.LogoutContext(context.Background())
+}
+
+// LogoutContext is like Logout but uses ctx for the request
+func (c *Client) LogoutContext(ctx context.Context) (ArubaAuthResp, error) {
+	req, err := c.genGetReq(ctx, "/api/logout")
 	if err != nil {
 		return ArubaAuthResp{}, err
 	}
-	req.AddCookie(c.cookie)
-	res, err := c.http.Do(req)
+	if cookie, _ := c.session(); cookie != nil {
+		req.AddCookie(cookie)
+	}
+	_, resp, err := c.send(req)
 	if err != nil {
-		return ArubaAuthResp{}, fmt.Errorf("failed to logout: %v", err)
+		return ArubaAuthResp{}, err
 	}
-	defer res.Body.Close()
 
 	var authObj ArubaAuthResp
-	json.NewDecoder(res.Body).Decode(&authObj)
+	if err = c.decode(req, resp, &authObj); err != nil {
+		return authObj, err
+	}
 	if authObj.GlobalRes.StatusStr == "You've been logged out successfully" {
-		c.cookie = nil
-		c.uidAruba = ""
+		c.setSession(nil, "")
 		return authObj, nil
 	}
 	return authObj, nil
 }
 
 // genGetReq returns a new http.Request object for a GET with the BaseURL as prefix to url
-func (c *Client) genGetReq(url string) (*http.Request, error) {
-	return http.NewRequest(http.MethodGet, c.BaseURL+url, nil)
+func (c *Client) genGetReq(ctx context.Context, url string) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+url, nil)
+}
+
+// genPostReq returns a new http.Request object for a POST with the BaseURL as prefix to url
+func (c *Client) genPostReq(ctx context.Context, url string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+url, body)
 }
 
 // AFilter URI Params for Get Reqs
 type AFilter struct {
 	Count   int
 	CfgPath string
+	// Offset and Limit select a page of the result, EachMMApDB uses them to walk all pages
+	Offset int
+	Limit  int
+	// Filters applied by the API, empty values are ignored
+	ApGroup  string
+	Model    string
+	Status   string
+	SwitchIP string
 }
 
-// updateReq enhances a http.Request object with query values needed to query ArubaOS
+// updateReq enhances a http.Request object with query values needed to query ArubaOS.
+// The session cookie and UIDARUBA token are added by do when the request is sent.
 func (c *Client) updateReq(req *http.Request, qs map[string]string) {
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Accept", "application/json")
-	req.AddCookie(c.cookie)
 	q := req.URL.Query()
 	for key, val := range qs {
 		q.Add(key, val)
 	}
-	q.Add("UIDARUBA", c.uidAruba)
 	req.URL.RawQuery = q.Encode()
 }
 
@@ -144,33 +196,16 @@ type WirelessClient struct {
 
 // GetClients ...
 func (c *Client) GetClients() ([]WirelessClient, error) {
+	return c.GetClientsContext(context.Background())
+}
+
+// GetClientsContext is like GetClients but uses ctx for the request
+func (c *Client) GetClientsContext(ctx context.Context) ([]WirelessClient, error) {
 	var clients []WirelessClient
-	if c.cookie == nil {
-		return clients, errors.New("missing cookie")
-	}
-	req, err := c.genGetReq("/configuration/showcommand")
+	res, err := c.ShowCommand(ctx, "show global-user-table list")
 	if err != nil {
 		return clients, err
 	}
-	cmd := "show global-user-table list"
-	qs := map[string]string{"command": cmd}
-	c.updateReq(req, qs)
-	res, err := c.http.Do(req)
-	if err != nil {
-		return clients, err
-	}
-	defer res.Body.Close()
-	type ClientResp map[string][]WirelessClient
-	var clientResp ClientResp
-	json.NewDecoder(res.Body).Decode(&clientResp)
-	clients = clientResp["Global Users"]
-	return clients, nil
-}
-
-// ControllerLicense ...
-type ControllerLicense struct {
-	Expires     string    `json:"Expires(Grace period expiry)"`
-	Installed   time.Time `json:"Installed"`
-	Key         string    `json:"Key"`
-	ServiceType string    `json:"Service Type"`
+	err = res.DecodeTable("Global Users", &clients)
+	return clients, err
 }
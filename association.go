@@ -0,0 +1,188 @@
+package arubaos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+{
+  "Association Table": [
+    {
+      "Band steer moves (T/S)": "0/0",
+      "Flags": "WVAB",
+      "Name": "ap01.bsc.norton.cafb.al",
+      "aid": "2",
+      "assoc": "y",
+      "assoc. time": "19m:0s",
+      "auth": "y",
+      "bssid": "a8:bd:27:5e:a8:12",
+      "essid": "MyResNet-5G",
+      "l-int": "20",
+      "mac": "88:a4:79:cd:30:47",
+      "num assoc": "1",
+      "phy": "a-VHT-40sgi-2ss",
+      "phy_cap": "a-HE-80-2ss-V",
+      "tunnel-id": "0x10a35",
+      "vlan-id": "1180"
+	},
+*/
+
+// AssocFlags the meaning of the letters in the Flags column of "show ap association"
+var AssocFlags = map[rune]string{
+	'W': "WMM client",
+	'A': "Active",
+	'B': "Band Steerable",
+	'K': "802.11K Capable",
+	'H': "HT client",
+	'R': "802.11R client",
+	'V': "802.11v BSS Trans capable",
+	'P': "Punctured preamble",
+	'U': "HE client",
+	'G': "6GHz Band Steerable",
+	'M': "Mu beam formee",
+	'2': "SU beam formee",
+	'O': "OWE client",
+	'S': "SAE client",
+	'E': "Enterprise client",
+	'm': "Agile Multiband client",
+	'C': "Cellular Data Capable - available",
+	'c': "Cellular Data Capable - unavailable",
+	'p': "802.11mc FTM responder capable",
+	'T': "Individual TWT client",
+}
+
+// PhyType a decoded phy or phy_cap value such as "a-VHT-40sgi-2ss"
+type PhyType struct {
+	Raw            string
+	Band           string // a or g
+	Mode           string // HT, VHT, HE, empty for legacy clients
+	WidthMHz       int
+	ShortGI        bool
+	SpatialStreams int
+	Extra          []string // anything that is not understood, e.g. V in "a-HE-80-2ss-V"
+}
+
+// parsePhy decodes a phy string from "show ap association"
+func parsePhy(s string) PhyType {
+	phy := PhyType{Raw: s}
+	for i, part := range strings.Split(s, "-") {
+		switch {
+		case part == "":
+		case i == 0:
+			phy.Band = part
+		case part == "HT" || part == "VHT" || part == "HE" || part == "EHT":
+			phy.Mode = part
+		case strings.HasSuffix(part, "ss"):
+			if n, err := strconv.Atoi(strings.TrimSuffix(part, "ss")); err == nil {
+				phy.SpatialStreams = n
+			} else {
+				phy.Extra = append(phy.Extra, part)
+			}
+		default:
+			width := strings.TrimSuffix(part, "sgi")
+			if n, err := strconv.Atoi(width); err == nil {
+				phy.WidthMHz = n
+				phy.ShortGI = width != part
+			} else {
+				phy.Extra = append(phy.Extra, part)
+			}
+		}
+	}
+	return phy
+}
+
+// Association one client in "show ap association"
+type Association struct {
+	ApName    string
+	MAC       string
+	BSSID     string
+	ESSID     string
+	AID       int
+	Assoc     bool
+	Auth      bool
+	AssocTime time.Duration
+	NumAssoc  int
+	ListenInt int
+	TunnelID  string
+	VlanID    int
+	// BandSteerMoves and BandSteerSteered are the T/S values in "Band steer moves (T/S)"
+	BandSteerMoves   int
+	BandSteerSteered int
+	Flags            string
+	Phy              PhyType
+	PhyCap           PhyType
+}
+
+// FlagNames returns the meaning of each letter in Flags, unknown letters are returned as is
+func (a Association) FlagNames() []string {
+	var names []string
+	for _, r := range a.Flags {
+		if name, ok := AssocFlags[r]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, string(r))
+		}
+	}
+	return names
+}
+
+// UnmarshalJSON decodes a row from the Association Table
+func (a *Association) UnmarshalJSON(b []byte) error {
+	var row ShowRow
+	if err := json.Unmarshal(b, &row); err != nil {
+		return err
+	}
+	*a = Association{
+		ApName:    row.String("Name"),
+		MAC:       row.String("mac"),
+		BSSID:     row.String("bssid"),
+		ESSID:     row.String("essid"),
+		AID:       atoi(row.String("aid")),
+		Assoc:     row.String("assoc") == "y",
+		Auth:      row.String("auth") == "y",
+		AssocTime: parseColonDuration(row.String("assoc. time")),
+		NumAssoc:  atoi(row.String("num assoc")),
+		ListenInt: atoi(row.String("l-int")),
+		TunnelID:  row.String("tunnel-id"),
+		VlanID:    atoi(row.String("vlan-id")),
+		Flags:     row.String("Flags"),
+		Phy:       parsePhy(row.String("phy")),
+		PhyCap:    parsePhy(row.String("phy_cap")),
+	}
+	if moves := strings.SplitN(row.String("Band steer moves (T/S)"), "/", 2); len(moves) == 2 {
+		a.BandSteerMoves = atoi(moves[0])
+		a.BandSteerSteered = atoi(moves[1])
+	}
+	return nil
+}
+
+// GetApAssociations returns the clients associated with an AP from "show ap association"
+// Can only be run on the Controller the AP is Registered with
+func (c *Client) GetApAssociations(ctx context.Context, apName string) ([]Association, error) {
+	res, err := c.ShowCommand(ctx, fmt.Sprintf("show ap association ap-name %s", apName))
+	if err != nil {
+		return nil, err
+	}
+	var assocs []Association
+	if err = res.DecodeTable("Association Table", &assocs); err != nil {
+		return nil, err
+	}
+	return assocs, nil
+}
+
+// GetApAssocCount returns the number of Clients Registered with a Specific AP
+// Can only be run on the Controller the AP is Registered with
+func (c *Client) GetApAssocCount(apName string) (int, error) {
+	return c.GetApAssocCountContext(context.Background(), apName)
+}
+
+// GetApAssocCountContext is like GetApAssocCount but uses ctx for the request
+func (c *Client) GetApAssocCountContext(ctx context.Context, apName string) (int, error) {
+	assocs, err := c.GetApAssociations(ctx, apName)
+	return len(assocs), err
+}
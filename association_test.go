@@ -0,0 +1,24 @@
+package arubaos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePhy(t *testing.T) {
+	tests := []struct {
+		in   string
+		want PhyType
+	}{
+		{"a-VHT-80sgi-2ss", PhyType{Raw: "a-VHT-80sgi-2ss", Band: "a", Mode: "VHT", WidthMHz: 80, ShortGI: true, SpatialStreams: 2}},
+		{"g-HT-20-1ss", PhyType{Raw: "g-HT-20-1ss", Band: "g", Mode: "HT", WidthMHz: 20, SpatialStreams: 1}},
+		{"a-HE-80-2ss-V", PhyType{Raw: "a-HE-80-2ss-V", Band: "a", Mode: "HE", WidthMHz: 80, SpatialStreams: 2, Extra: []string{"V"}}},
+		{"g", PhyType{Raw: "g", Band: "g"}},
+		{"", PhyType{}},
+	}
+	for _, tt := range tests {
+		if got := parsePhy(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePhy(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
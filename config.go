@@ -0,0 +1,53 @@
+package arubaos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// WriteMemory saves the configuration at configPath so it survives a reload, ArubaOS does not
+// do this by itself after changes made through the API.
+// configPath is used as in GetObject.
+func (c *Client) WriteMemory(ctx context.Context, configPath string) error {
+	if !c.loggedIn() {
+		return ErrNotLoggedIn
+	}
+	qs := map[string]string{}
+	if configPath != "" {
+		qs["config_path"] = configPath
+	}
+	_, err := c.postObject(ctx, "/configuration/object/write_memory", qs, struct{}{})
+	return err
+}
+
+// PendingChanges lists the changes at configPath that are not yet saved with WriteMemory,
+// one line per change as shown by "show configuration pending"
+func (c *Client) PendingChanges(ctx context.Context, configPath string) ([]string, error) {
+	qs := map[string]string{}
+	if configPath != "" {
+		qs["config_path"] = configPath
+	}
+	res, err := c.showCommand(ctx, "show configuration pending", qs)
+	if err != nil {
+		return nil, err
+	}
+	var changes []string
+	for _, line := range res.Data {
+		if line = strings.TrimSpace(line); line != "" {
+			changes = append(changes, line)
+		}
+	}
+	return changes, nil
+}
+
+// commit saves configPath after a successful write when AutoCommit is set
+func (c *Client) commit(ctx context.Context, configPath string) error {
+	if !c.AutoCommit {
+		return nil
+	}
+	if err := c.WriteMemory(ctx, configPath); err != nil {
+		return fmt.Errorf("configuration was changed but not saved: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package arubaos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrNotLoggedIn is returned when a method is called before Login
+	ErrNotLoggedIn = errors.New("you must first login to perform this action")
+	// ErrSessionExpired matches an *APIError that was caused by an expired or invalid session
+	ErrSessionExpired = errors.New("session expired")
+)
+
+// APIError is returned when ArubaOS answers a request with an error, either as an HTTP status or in _global_result
+type APIError struct {
+	HTTPStatus int    // HTTP status code of the response
+	Status     int    // status from _global_result
+	StatusStr  string // status_str from _global_result
+	Endpoint   string // endpoint relative to BaseURL, e.g. /configuration/showcommand
+	Command    string // show command or configuration object, if any
+	expired    bool
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Endpoint)
+	if e.Command != "" {
+		fmt.Fprintf(&b, " (%s)", e.Command)
+	}
+	fmt.Fprintf(&b, ": http status %d", e.HTTPStatus)
+	if e.Status != 0 || e.StatusStr != "" {
+		fmt.Fprintf(&b, ", status %d: %s", e.Status, e.StatusStr)
+	}
+	return b.String()
+}
+
+// Is makes errors.Is(err, ErrSessionExpired) true for session errors
+func (e *APIError) Is(target error) bool {
+	return target == ErrSessionExpired && e.expired
+}
+
+// DecodeError is returned when a response body could not be parsed
+type DecodeError struct {
+	Endpoint string
+	Command  string
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("error parsing resp body from %s: %v", e.Endpoint, e.Err)
+}
+
+// Unwrap returns the underlying decoding error
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// TransportError is returned when a request did not get any response, e.g. on timeouts or refused connections
+type TransportError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("request to %s failed: %v", e.Endpoint, e.Err)
+}
+
+// Unwrap returns the underlying error from the http.Client
+func (e *TransportError) Unwrap() error { return e.Err }
+
+// endpoint returns the path of req relative to BaseURL
+func (c *Client) endpoint(req *http.Request) string {
+	if base, err := url.Parse(c.BaseURL); err == nil {
+		return strings.TrimPrefix(req.URL.Path, base.Path)
+	}
+	return req.URL.Path
+}
+
+// command returns the show command or configuration object req is about
+func (c *Client) command(req *http.Request) string {
+	if cmd := req.URL.Query().Get("command"); cmd != "" {
+		return cmd
+	}
+	if path := c.endpoint(req); strings.HasPrefix(path, "/configuration/object/") {
+		return strings.TrimPrefix(path, "/configuration/object/")
+	}
+	return ""
+}
+
+// decode parses the body of a response to req into v
+func (c *Client) decode(req *http.Request, body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return &DecodeError{Endpoint: c.endpoint(req), Command: c.command(req), Err: err}
+	}
+	return nil
+}
@@ -3,6 +3,6 @@ module github.com/helgeolav/arubaos
 go 1.13
 
 require (
-	bitbucket.org/HelgeOlav/utils v0.0.0-20220701084959-c069f6bb0a4f // indirect
+	bitbucket.org/HelgeOlav/utils v0.0.0-20220701084959-c069f6bb0a4f
 	github.com/subosito/gotenv v1.4.0
 )
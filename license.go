@@ -0,0 +1,181 @@
+package arubaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// licenseTimeLayouts the date formats ArubaOS uses in "show license"
+var licenseTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"Mon Jan _2 15:04:05 2006",
+	"Mon Jan _2 15:04:05 MST 2006",
+	"2006-01-02",
+	"01/02/2006",
+	"Jan _2 2006",
+}
+
+// parseLicenseTime parses a date from "show license", the second return value is false if
+// the value is not a date, e.g. "Never"
+func parseLicenseTime(s string) (time.Time, bool) {
+	// "2020-03-08 10:45:26 (30 days)" has the grace period after the date
+	if i := strings.Index(s, "("); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range licenseTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ControllerLicense a license installed on the controller
+type ControllerLicense struct {
+	Expires     string    `json:"Expires(Grace period expiry)"`
+	Installed   time.Time `json:"-"`
+	Key         string    `json:"Key"`
+	ServiceType string    `json:"Service Type"`
+	Flags       string    `json:"Flags"`
+	// ExpiresAt is parsed from Expires, it is zero for permanent licenses
+	ExpiresAt time.Time `json:"-"`
+}
+
+// UnmarshalJSON parses Installed and Expires in the formats used by ArubaOS
+func (l *ControllerLicense) UnmarshalJSON(b []byte) error {
+	type license ControllerLicense
+	raw := struct {
+		*license
+		Installed string `json:"Installed"`
+	}{license: (*license)(l)}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	l.Installed, _ = parseLicenseTime(raw.Installed)
+	l.ExpiresAt, _ = parseLicenseTime(l.Expires)
+	return nil
+}
+
+// Permanent returns true if the license does not expire
+func (l ControllerLicense) Permanent() bool {
+	return l.ExpiresAt.IsZero()
+}
+
+// ExpiresIn returns the time left until the license expires, negative if it has expired.
+// Permanent licenses return 0.
+func (l ControllerLicense) ExpiresIn(now time.Time) time.Duration {
+	if l.Permanent() {
+		return 0
+	}
+	return l.ExpiresAt.Sub(now)
+}
+
+// LicenseUsage the usage of one license pool, from "show license-usage <pool>" on the Mobility Master
+type LicenseUsage struct {
+	Pool      string // the pool argument to show license-usage, e.g. ap
+	Total     int    // the "Overall ... License Limit" row, e.g. "Overall AP License Limit"
+	Used      int    // the "Total ..." usage row, e.g. "Total APs"
+	Remaining int
+	// Counters holds every Type/Number and Type/Count row of the command, e.g. Counters["Active CAPs"]
+	Counters map[string]int
+}
+
+// LicenseInventory the licenses on a controller and the pool usage
+type LicenseInventory struct {
+	Licenses []ControllerLicense
+	Usage    []LicenseUsage
+}
+
+// ExpiringWithin returns the licenses that expire within d from now, including those that have expired
+func (inv *LicenseInventory) ExpiringWithin(d time.Duration, now time.Time) []ControllerLicense {
+	var expiring []ControllerLicense
+	for _, l := range inv.Licenses {
+		if !l.Permanent() && l.ExpiresIn(now) <= d {
+			expiring = append(expiring, l)
+		}
+	}
+	return expiring
+}
+
+// DefaultLicensePools the pools GetLicenses reads when no pools are given
+var DefaultLicensePools = []string{"ap", "pef", "rfp"}
+
+// GetLicenses returns the installed licenses from "show license" and the usage of each pool from
+// "show license-usage <pool>", DefaultLicensePools if no pools are given. Pools whose command
+// the controller does not support are left out of Usage.
+func (c *Client) GetLicenses(ctx context.Context, pools ...string) (*LicenseInventory, error) {
+	res, err := c.ShowCommand(ctx, "show license")
+	if err != nil {
+		return nil, err
+	}
+	inv := &LicenseInventory{}
+	if err = res.DecodeTable("License Table", &inv.Licenses); err != nil {
+		return nil, err
+	}
+	if len(pools) == 0 {
+		pools = DefaultLicensePools
+	}
+	for _, pool := range pools {
+		usage, err := c.getLicenseUsage(ctx, pool)
+		switch {
+		case err == nil:
+			inv.Usage = append(inv.Usage, usage)
+		case !isUnsupportedCommand(err):
+			return inv, err
+		}
+	}
+	return inv, nil
+}
+
+// isUnsupportedCommand returns true if err is the CLI rejecting a show command that does not exist
+func isUnsupportedCommand(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus >= http.StatusInternalServerError || errors.Is(err, ErrSessionExpired) {
+		return false
+	}
+	s := strings.ToLower(apiErr.StatusStr)
+	for _, msg := range []string{"invalid input", "parse error", "not supported", "unrecognized command"} {
+		if strings.Contains(s, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// getLicenseUsage reads "show license-usage <pool>", which has Type/Number tables for the
+// licenses and Type/Count tables for the usage
+func (c *Client) getLicenseUsage(ctx context.Context, pool string) (LicenseUsage, error) {
+	res, err := c.ShowCommand(ctx, "show license-usage "+pool)
+	if err != nil {
+		return LicenseUsage{}, err
+	}
+	usage := LicenseUsage{Pool: pool, Counters: map[string]int{}}
+	for _, name := range res.TableNames() {
+		rows, err := res.Rows(name)
+		if err != nil {
+			return LicenseUsage{}, err
+		}
+		for _, row := range rows {
+			if typ := row.String("Type"); typ != "" {
+				usage.Counters[typ] = atoi(row.String("Number", "Count"))
+			}
+		}
+	}
+	for typ, n := range usage.Counters {
+		switch {
+		case strings.HasPrefix(typ, "Overall ") && strings.HasSuffix(typ, " License Limit"):
+			usage.Total = n
+		case strings.HasPrefix(typ, "Total ") && !strings.Contains(typ, "License"):
+			usage.Used = n
+		}
+	}
+	if usage.Total > usage.Used {
+		usage.Remaining = usage.Total - usage.Used
+	}
+	return usage, nil
+}
@@ -0,0 +1,57 @@
+package arubaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetLicenses(t *testing.T) {
+	c, f := newFakeController(t, func(w http.ResponseWriter, r *http.Request, valid bool) {
+		switch r.URL.Query().Get("command") {
+		case "show license":
+			fmt.Fprint(w, `{"License Table":[{"Key":"k1","Installed":"2020-03-08 10:45:26","Expires(Grace period expiry)":"Never","Service Type":"Access Point"}]}`)
+		case "show license-usage ap":
+			fmt.Fprint(w, `{"AP Licenses":[{"Type":"AP Licenses","Number":"64"},{"Type":"Overall AP License Limit","Number":"64"}],`+
+				`"AP Usage":[{"Type":"Active CAPs","Count":"40"},{"Type":"Total APs","Count":"40"}]}`)
+		case "show license-usage pef":
+			fmt.Fprint(w, `{"PEF Licenses":[{"Type":"Overall PEF License Limit","Number":"32"}],"PEF Usage":[{"Type":"Total PEF APs","Count":"40"}]}`)
+		default:
+			fmt.Fprint(w, `{"_global_result":{"status":1,"status_str":"% Invalid input detected at '^' marker."}}`)
+		}
+	})
+	defer f.Close()
+	inv, err := c.GetLicenses(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inv.Licenses) != 1 || !inv.Licenses[0].Permanent() {
+		t.Errorf("Licenses = %+v", inv.Licenses)
+	}
+	want := []LicenseUsage{
+		{Pool: "ap", Total: 64, Used: 40, Remaining: 24, Counters: map[string]int{"AP Licenses": 64, "Overall AP License Limit": 64, "Active CAPs": 40, "Total APs": 40}},
+		{Pool: "pef", Total: 32, Used: 40, Counters: map[string]int{"Overall PEF License Limit": 32, "Total PEF APs": 40}},
+	}
+	if !reflect.DeepEqual(inv.Usage, want) {
+		t.Errorf("Usage = %+v, want %+v", inv.Usage, want)
+	}
+}
+
+func TestGetLicensesUsageError(t *testing.T) {
+	c, f := newFakeController(t, func(w http.ResponseWriter, r *http.Request, valid bool) {
+		if r.URL.Query().Get("command") == "show license" {
+			fmt.Fprint(w, `{"License Table":[]}`)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer f.Close()
+	_, err := c.GetLicenses(context.Background(), "ap")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusInternalServerError {
+		t.Errorf("GetLicenses() error = %v, want an *APIError with status 500", err)
+	}
+}
@@ -0,0 +1,115 @@
+package arubaos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// APLldp the properties of a Neighbor Connected to the AP
+type APLldp struct {
+	APName         string `json:"AP"`
+	RemoteHostname string `json:"Chassis Name/ID"`
+	RemoteIP       string `json:"Mgmt. Address"`
+	RemoteIntfDesc string `json:"Port Desc"`
+	RemoteIntf     string `json:"Port ID"`
+}
+
+// GetApLLDPInfo gets LLDP Info of Device Connecting to the AP
+// This Command MUST be run from the Controller *NOT MM
+// Only the last neighbor is returned, see GetApLLDPNeighbors for all neighbors of all APs
+func (c *Client) GetApLLDPInfo(apName string) (APLldp, error) {
+	return c.GetApLLDPInfoContext(context.Background(), apName)
+}
+
+// GetApLLDPInfoContext is like GetApLLDPInfo but uses ctx for the request
+func (c *Client) GetApLLDPInfoContext(ctx context.Context, apName string) (APLldp, error) {
+	neighbors, err := c.getLLDPNeighbors(ctx, fmt.Sprintf("show ap lldp neighbors ap-name %s", apName))
+	if err != nil || len(neighbors) == 0 {
+		return APLldp{}, err
+	}
+	n := neighbors[len(neighbors)-1]
+	return APLldp{
+		APName:         n.APName,
+		RemoteHostname: n.RemoteHostname,
+		RemoteIP:       n.RemoteIP,
+		RemoteIntfDesc: n.RemoteIntfDesc,
+		RemoteIntf:     n.RemoteIntf,
+	}, nil
+}
+
+// LLDPNeighbor a device seen by an AP through LLDP
+type LLDPNeighbor struct {
+	APName         string
+	Interface      string // the AP port, e.g. eth0
+	RemoteHostname string
+	RemoteIP       string
+	RemoteIntf     string
+	RemoteIntfDesc string
+	Capabilities   []string
+	VlanID         int
+	PoEPowerWatt   float64 // power negotiated with the switch, 0 if not reported
+}
+
+// GetApLLDPNeighbors returns the LLDP neighbors of every AP on the controller in one request, keyed by AP name
+// This Command MUST be run from the Controller *NOT MM
+func (c *Client) GetApLLDPNeighbors(ctx context.Context) (map[string][]LLDPNeighbor, error) {
+	neighbors, err := c.getLLDPNeighbors(ctx, "show ap lldp neighbors")
+	if err != nil {
+		return nil, err
+	}
+	byAp := map[string][]LLDPNeighbor{}
+	for _, n := range neighbors {
+		byAp[n.APName] = append(byAp[n.APName], n)
+	}
+	return byAp, nil
+}
+
+// getLLDPNeighbors runs a show ap lldp neighbors command
+func (c *Client) getLLDPNeighbors(ctx context.Context, cmd string) ([]LLDPNeighbor, error) {
+	res, err := c.ShowCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	var neighbors []LLDPNeighbor
+	// The name of the returned table is
+	// Dynamic/Non-Deterministic, so every
+	// table in the response is PARSED
+	for _, name := range res.TableNames() {
+		rows, err := res.Rows(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			neighbors = append(neighbors, LLDPNeighbor{
+				APName:         row.String("AP", "AP Name"),
+				Interface:      row.String("Interface", "Intf", "Local Intf"),
+				RemoteHostname: row.String("Chassis Name/ID", "System Name"),
+				RemoteIP:       row.String("Mgmt. Address", "Management Address"),
+				RemoteIntf:     row.String("Port ID"),
+				RemoteIntfDesc: row.String("Port Desc", "Port Description"),
+				Capabilities:   splitCapabilities(row.String("Capabilities", "Capability", "System Capabilities")),
+				VlanID:         atoi(row.String("VLAN", "Vlan ID", "VLAN ID", "PVID")),
+				PoEPowerWatt:   parsePowerWatt(row.String("PoE Power", "Power Negotiated", "PoE Power Negotiated", "Power")),
+			})
+		}
+	}
+	return neighbors, nil
+}
+
+// splitCapabilities splits "B,R" or "Bridge, Router" into its parts
+func splitCapabilities(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+}
+
+// parsePowerWatt parses "25.5W", "25.5 W" or "25500 mW"
+func parsePowerWatt(s string) float64 {
+	n, unit, ok := parseNumberPrefix(s)
+	if !ok {
+		return 0
+	}
+	if strings.HasPrefix(unit, "mw") {
+		n /= 1000
+	}
+	return n
+}
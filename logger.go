@@ -0,0 +1,138 @@
+package arubaos
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// LogLevel the severity of a log message
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota // requests and responses
+	LogInfo
+	LogWarn // e.g. logging in again after an expired session
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	}
+	return "UNKNOWN"
+}
+
+// Logger receives the log messages of a Client. Passwords, session cookies and
+// UIDARUBA tokens are redacted before they are passed to the Logger.
+type Logger interface {
+	Logf(level LogLevel, format string, args ...interface{})
+}
+
+// stdLogger writes to a *log.Logger
+type stdLogger struct {
+	l   *log.Logger
+	min LogLevel
+}
+
+// NewStdLogger returns a Logger that writes messages at level min and above to l
+func NewStdLogger(l *log.Logger, min LogLevel) Logger {
+	return &stdLogger{l: l, min: min}
+}
+
+// Logf writes the message with the level as prefix
+func (s *stdLogger) Logf(level LogLevel, format string, args ...interface{}) {
+	if level >= s.min {
+		s.l.Printf(level.String()+" "+format, args...)
+	}
+}
+
+// maxLogBody the number of bytes of a request or response body that are logged
+const maxLogBody = 4096
+
+// redactPatterns match secrets in URLs, form and JSON bodies, the first group is kept
+var redactPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(UIDARUBA=)[^&\s"]+`),
+	regexp.MustCompile(`(?i)(password=)[^&\s"]+`),
+	regexp.MustCompile(`(?i)("(?:UIDARUBA|password|passphrase|wpa-passphrase)"\s*:\s*")[^"]*`),
+}
+
+// redacted replaces secrets with this text
+const redacted = "REDACTED"
+
+// redact removes the password, session cookie and UIDARUBA token from s
+func (c *Client) redact(s string) string {
+	cookie, uid := c.session()
+	secrets := []string{c.Password, uid}
+	if cookie != nil {
+		secrets = append(secrets, cookie.Value)
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.Replace(s, secret, redacted, -1)
+		}
+	}
+	for _, re := range redactPatterns {
+		s = re.ReplaceAllString(s, "${1}"+redacted)
+	}
+	return s
+}
+
+// logf sends a message to Logger if one is set
+func (c *Client) logf(level LogLevel, format string, args ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Logf(level, format, args...)
+	}
+}
+
+// logRequest logs req and its body at debug level
+func (c *Client) logRequest(req *http.Request) {
+	if c.Logger == nil {
+		return
+	}
+	var body []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(io.LimitReader(rc, maxLogBody))
+			rc.Close()
+		}
+	}
+	c.logf(LogDebug, "request %s %s %s", req.Method, c.redact(req.URL.String()), c.redact(string(body)))
+}
+
+// logResponse logs the response to req at debug level
+func (c *Client) logResponse(req *http.Request, res *http.Response, body []byte) {
+	if c.Logger == nil {
+		return
+	}
+	logged := body
+	if len(logged) > maxLogBody {
+		logged = logged[:maxLogBody]
+	}
+	c.logf(LogDebug, "response %s %s: http status %d, %d bytes %s", req.Method, c.endpoint(req), res.StatusCode, len(body), c.redact(string(logged)))
+}
+
+// redactURLError removes the query string, which holds the UIDARUBA token, from the URL in err
+func redactURLError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil && u.RawQuery != "" {
+			q := u.Query()
+			if q.Get("UIDARUBA") != "" {
+				q.Set("UIDARUBA", redacted)
+			}
+			u.RawQuery = q.Encode()
+			urlErr.URL = u.String()
+		}
+	}
+	return err
+}
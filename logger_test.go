@@ -0,0 +1,40 @@
+package arubaos
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	c := &Client{Password: "pa$$word"}
+	c.setSession(&http.Cookie{Name: "SESSION", Value: "cookie-value"}, "uid-token")
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://mm:4343/v1/configuration/object?UIDARUBA=uid-token&config_path=%2Fmd",
+			"https://mm:4343/v1/configuration/object?UIDARUBA=REDACTED&config_path=%2Fmd"},
+		{"?uidaruba=other-token", "?uidaruba=REDACTED"},
+		{"password=pa%24%24word&username=admin", "password=REDACTED&username=admin"},
+		{`{"_global_result":{"UIDARUBA":"new-token"}}`, `{"_global_result":{"UIDARUBA":"REDACTED"}}`},
+		{`{"wpa_passphrase":{"wpa-passphrase":"secret"}}`, `{"wpa_passphrase":{"wpa-passphrase":"REDACTED"}}`},
+		{"SESSION=cookie-value", "SESSION=REDACTED"},
+		{"the password is pa$$word", "the password is REDACTED"},
+		{"show ap database long", "show ap database long"},
+	}
+	for _, tt := range tests {
+		if got := c.redact(tt.in); got != tt.want {
+			t.Errorf("redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactURLError(t *testing.T) {
+	err := redactURLError(&url.Error{Op: "Get", URL: "https://mm/v1/configuration/showcommand?UIDARUBA=uid-token&command=show+ap", Err: errors.New("timeout")})
+	if msg := err.Error(); strings.Contains(msg, "uid-token") || !strings.Contains(msg, "UIDARUBA=REDACTED") {
+		t.Errorf("redactURLError() = %q", msg)
+	}
+}
@@ -0,0 +1,339 @@
+package arubaos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrObjectNotFound is returned when a named configuration object does not exist at the config_path
+var ErrObjectNotFound = errors.New("configuration object not found")
+
+// ProfileRef a reference from one configuration object to a profile
+type ProfileRef struct {
+	Name   string `json:"profile-name"`
+	Action string `json:"_action,omitempty"`
+}
+
+// ObjectResult the decoded response from a POST to /configuration/object
+type ObjectResult struct {
+	Status    int          // status from _global_result
+	StatusStr string       // status_str from _global_result
+	Items     []ItemResult // result of each object, in the order they were sent, objects in _list first
+}
+
+// ItemResult the result of one object in a POST to /configuration/object
+type ItemResult struct {
+	Index     int    // position in _list, -1 for objects sent outside _list
+	Object    string // name of the object, e.g. ap_rename
+	Status    int
+	StatusStr string
+}
+
+// Failed returns the items that did not succeed
+func (r *ObjectResult) Failed() []ItemResult {
+	var failed []ItemResult
+	for _, item := range r.Items {
+		if item.Status != 0 {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// ObjectError is returned when ArubaOS reports that one or more objects in a POST failed.
+// Err holds the *APIError for the request if _global_result also reported an error.
+type ObjectError struct {
+	Endpoint string
+	Result   *ObjectResult
+	Err      error
+}
+
+func (e *ObjectError) Error() string {
+	failed := e.Result.Failed()
+	if len(failed) == 0 {
+		if e.Err != nil {
+			return e.Err.Error()
+		}
+		return fmt.Sprintf("%s: status %d: %s", e.Endpoint, e.Result.Status, e.Result.StatusStr)
+	}
+	return fmt.Sprintf("%s: %d of %d objects failed, first %s: %s", e.Endpoint, len(failed), len(e.Result.Items),
+		failed[0].Object, failed[0].StatusStr)
+}
+
+// Unwrap returns the *APIError for the request, if any
+func (e *ObjectError) Unwrap() error { return e.Err }
+
+// postObject sends payload as JSON to endpoint below /configuration/object and decodes the result.
+// An *ObjectError is returned together with the result if anything failed.
+func (c *Client) postObject(ctx context.Context, endpoint string, qs map[string]string, payload interface{}) (*ObjectResult, error) {
+	j, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.genPostReq(ctx, endpoint, bytes.NewReader(j))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.updateReq(req, qs)
+	resp, err := c.do(req)
+	var apiErr *APIError
+	if err != nil && !errors.As(err, &apiErr) {
+		return nil, err
+	}
+	result, decodeErr := c.decodeObjectResult(req, resp)
+	if decodeErr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, decodeErr
+	}
+	if err != nil || result.Status != 0 || len(result.Failed()) > 0 {
+		return result, &ObjectError{Endpoint: c.endpoint(req), Result: result, Err: err}
+	}
+	return result, nil
+}
+
+// postConfig is like postObject for writes that change the configuration,
+// with AutoCommit the config_path in qs is saved after a successful write.
+// Operational actions such as reboots use postObject and are never saved.
+func (c *Client) postConfig(ctx context.Context, endpoint string, qs map[string]string, payload interface{}) (*ObjectResult, error) {
+	result, err := c.postObject(ctx, endpoint, qs, payload)
+	if err != nil {
+		return result, err
+	}
+	return result, c.commit(ctx, qs["config_path"])
+}
+
+// decodeObjectResult parses _global_result and the _result of each object, either
+// from the entries in _list or from objects at the top level of the response
+func (c *Client) decodeObjectResult(req *http.Request, body []byte) (*ObjectResult, error) {
+	names, raw, err := orderedObject(body)
+	if err != nil {
+		return nil, &DecodeError{Endpoint: c.endpoint(req), Command: c.command(req), Err: err}
+	}
+	result := &ObjectResult{}
+	if gr, ok := raw["_global_result"]; ok {
+		var global globalResult
+		if err := c.decode(req, gr, &global); err != nil {
+			return nil, err
+		}
+		result.Status = int(global.Status)
+		result.StatusStr = global.StatusStr
+	}
+	if list, ok := raw["_list"]; ok {
+		var entries []json.RawMessage
+		if err := c.decode(req, list, &entries); err != nil {
+			return nil, err
+		}
+		for i, entry := range entries {
+			entryNames, objects, err := orderedObject(entry)
+			if err != nil {
+				return nil, &DecodeError{Endpoint: c.endpoint(req), Command: c.command(req), Err: err}
+			}
+			result.Items = append(result.Items, itemResults(i, entryNames, objects)...)
+		}
+	}
+	result.Items = append(result.Items, itemResults(-1, names, raw)...)
+	return result, nil
+}
+
+// orderedObject decodes a JSON object and returns its keys in the order they appear
+func orderedObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected a JSON object: %s", data)
+	}
+	var names []string
+	objects := map[string]json.RawMessage{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		name, _ := tok.(string)
+		var value json.RawMessage
+		if err = dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, seen := objects[name]; !seen {
+			names = append(names, name)
+		}
+		objects[name] = value
+	}
+	return names, objects, nil
+}
+
+// itemResults returns the _result of each object in names, ignoring keys starting with _
+func itemResults(index int, names []string, objects map[string]json.RawMessage) []ItemResult {
+	var items []ItemResult
+	for _, name := range names {
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+		var obj struct {
+			Result *globalResult `json:"_result"`
+		}
+		if err := json.Unmarshal(objects[name], &obj); err != nil || obj.Result == nil {
+			continue
+		}
+		items = append(items, ItemResult{Index: index, Object: name, Status: int(obj.Result.Status), StatusStr: obj.Result.StatusStr})
+	}
+	return items
+}
+
+// Operators that can be used in an ObjectFilter
+const (
+	FilterEq  = "$eq"
+	FilterNeq = "$neq"
+	FilterGt  = "$gt"
+	FilterGte = "$gte"
+	FilterLt  = "$lt"
+	FilterLte = "$lte"
+	FilterIn  = "$in"
+	FilterNin = "$nin"
+)
+
+// ObjectFilter limits what GetObject returns, Field is "<object>.<parameter>",
+// e.g. ObjectFilter{Field: "ssid_prof.profile-name", Op: FilterEq, Values: []string{"guest"}}
+type ObjectFilter struct {
+	Field  string
+	Op     string
+	Values []string
+}
+
+// encodeFilters returns filters in the format ArubaOS expects in the filter query parameter
+func encodeFilters(filters []ObjectFilter) (string, error) {
+	var list []map[string]map[string][]string
+	for _, f := range filters {
+		if f.Field == "" || f.Op == "" {
+			return "", fmt.Errorf("invalid filter %+v: field and op are required", f)
+		}
+		list = append(list, map[string]map[string][]string{f.Field: {f.Op: f.Values}})
+	}
+	j, err := json.Marshal(list)
+	return string(j), err
+}
+
+// ObjectData the decoded response from GET /configuration/object/<name>
+type ObjectData struct {
+	Name string
+	// Raw holds every key in the response
+	Raw map[string]json.RawMessage
+	// Data the value of the object inside _data, this is usually a list of objects
+	Data json.RawMessage
+}
+
+// Decode decodes the object inside _data into v, e.g. a pointer to a slice of structs
+func (d *ObjectData) Decode(v interface{}) error {
+	if len(d.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(d.Data, v); err != nil {
+		return &DecodeError{Endpoint: "/configuration/object/" + d.Name, Command: d.Name, Err: err}
+	}
+	return nil
+}
+
+// GetObject reads any configuration object, e.g. ssid_prof, virtual_ap or ap_group, at configPath.
+// An empty configPath leaves it to the controller to pick the default path, this is the same
+// for every method that takes a configPath.
+func (c *Client) GetObject(ctx context.Context, name, configPath string, filters []ObjectFilter) (*ObjectData, error) {
+	qs := map[string]string{}
+	if len(filters) > 0 {
+		filter, err := encodeFilters(filters)
+		if err != nil {
+			return nil, err
+		}
+		qs["filter"] = filter
+	}
+	return c.getObject(ctx, name, configPath, qs)
+}
+
+// getObject performs the GET for GetObject with the query values in qs
+func (c *Client) getObject(ctx context.Context, name, configPath string, qs map[string]string) (*ObjectData, error) {
+	if !c.loggedIn() {
+		return nil, ErrNotLoggedIn
+	}
+	req, err := c.genGetReq(ctx, "/configuration/object/"+name)
+	if err != nil {
+		return nil, err
+	}
+	if configPath != "" {
+		qs["config_path"] = configPath
+	}
+	c.updateReq(req, qs)
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	data := &ObjectData{Name: name}
+	if err = c.decode(req, resp, &data.Raw); err != nil {
+		return nil, err
+	}
+	if raw, ok := data.Raw["_data"]; ok {
+		var objects map[string]json.RawMessage
+		if err = c.decode(req, raw, &objects); err != nil {
+			return nil, err
+		}
+		data.Data = objects[name]
+	}
+	return data, nil
+}
+
+// Actions that can be set on an ObjectAction
+const (
+	ActionAdd    = "add"
+	ActionModify = "modify"
+	ActionDelete = "delete"
+)
+
+// ObjectAction one object to write with PostObjects
+type ObjectAction struct {
+	Name   string      // the object, e.g. ssid_prof
+	Action string      // optional _action, e.g. ActionDelete
+	Data   interface{} // the object itself, must encode to a JSON object
+}
+
+// MarshalJSON encodes the action as an entry in _list, {"<name>": {..., "_action": "<action>"}}
+func (a ObjectAction) MarshalJSON() ([]byte, error) {
+	obj := map[string]json.RawMessage{}
+	if a.Data != nil {
+		j, err := json.Marshal(a.Data)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(j, &obj); err != nil {
+			return nil, fmt.Errorf("data for %s is not a JSON object: %w", a.Name, err)
+		}
+		// data that encodes to null, e.g. a nil pointer, leaves obj nil
+		if obj == nil {
+			obj = map[string]json.RawMessage{}
+		}
+	}
+	if a.Action != "" {
+		obj["_action"], _ = json.Marshal(a.Action)
+	}
+	return json.Marshal(map[string]interface{}{a.Name: obj})
+}
+
+// PostObjects writes any number of configuration objects at configPath in one request.
+// The result holds the status of each object, in the same order as actions.
+// configPath is used as in GetObject.
+func (c *Client) PostObjects(ctx context.Context, configPath string, actions []ObjectAction) (*ObjectResult, error) {
+	if !c.loggedIn() {
+		return nil, ErrNotLoggedIn
+	}
+	qs := map[string]string{}
+	if configPath != "" {
+		qs["config_path"] = configPath
+	}
+	payload := struct {
+		List []ObjectAction `json:"_list"`
+	}{List: actions}
+	return c.postConfig(ctx, "/configuration/object", qs, payload)
+}
@@ -0,0 +1,76 @@
+package arubaos
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeObjectResult(t *testing.T) {
+	body := `{
+		"_global_result": {"status": 1, "status_str": "Error in one or more objects"},
+		"_list": [
+			{"ap_rename": {"_result": {"status": 0, "status_str": "Success"}},
+			 "ap_regroup": {"_result": {"status": 0, "status_str": "Success"}}},
+			{"ap_rename": {"_result": {"status": 1, "status_str": "AP not found"}},
+			 "ap_regroup": {"_result": {"status": "2", "status_str": "Group does not exist"}}}
+		],
+		"write_memory": {"_result": {"status": 0, "status_str": "Success"}}
+	}`
+	c := &Client{BaseURL: "https://mm:4343/v1"}
+	req := httptest.NewRequest("POST", "https://mm:4343/v1/configuration/object", nil)
+	got, err := c.decodeObjectResult(req, []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ObjectResult{
+		Status:    1,
+		StatusStr: "Error in one or more objects",
+		Items: []ItemResult{
+			{Index: 0, Object: "ap_rename", Status: 0, StatusStr: "Success"},
+			{Index: 0, Object: "ap_regroup", Status: 0, StatusStr: "Success"},
+			{Index: 1, Object: "ap_rename", Status: 1, StatusStr: "AP not found"},
+			{Index: 1, Object: "ap_regroup", Status: 2, StatusStr: "Group does not exist"},
+			{Index: -1, Object: "write_memory", Status: 0, StatusStr: "Success"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeObjectResult() = %+v, want %+v", got, want)
+	}
+	if failed := got.Failed(); len(failed) != 2 || failed[0].Index != 1 || failed[1].Index != 1 {
+		t.Errorf("Failed() = %+v", failed)
+	}
+}
+
+func TestDecodeObjectResultNotAnObject(t *testing.T) {
+	c := &Client{BaseURL: "https://mm:4343/v1"}
+	req := httptest.NewRequest("POST", "https://mm:4343/v1/configuration/object", nil)
+	if _, err := c.decodeObjectResult(req, []byte(`[1,2]`)); err == nil {
+		t.Error("decodeObjectResult() error = nil, want a *DecodeError")
+	}
+}
+
+func TestObjectActionMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		action ObjectAction
+		want   string
+	}{
+		{"data", ObjectAction{Name: "ap_group", Data: ProfileRef{Name: "g1"}}, `{"ap_group":{"profile-name":"g1"}}`},
+		{"action", ObjectAction{Name: "ap_group", Action: ActionDelete, Data: ProfileRef{Name: "g1"}}, `{"ap_group":{"_action":"delete","profile-name":"g1"}}`},
+		{"no data", ObjectAction{Name: "write_memory"}, `{"write_memory":{}}`},
+		{"nil pointer", ObjectAction{Name: "ap_group", Action: ActionDelete, Data: (*ApGroup)(nil)}, `{"ap_group":{"_action":"delete"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.action)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+	if _, err := json.Marshal(ObjectAction{Name: "ap_group", Data: []string{"g1"}}); err == nil {
+		t.Error("MarshalJSON() of a list error = nil")
+	}
+}
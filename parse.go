@@ -0,0 +1,96 @@
+package arubaos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// flexInt an int that ArubaOS sends either as a number or as a string, e.g. 0 or "0"
+type flexInt int
+
+// UnmarshalJSON accepts a number, a string holding a number, "" and null
+func (i *flexInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*i = 0
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	*i = flexInt(n)
+	return nil
+}
+
+// durationUnits the units ArubaOS uses in durations such as "1d:2h:19m:0s"
+var durationUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"h": time.Hour,
+	"m": time.Minute,
+	"s": time.Second,
+}
+
+// parseColonDuration parses durations like "19m:0s" or "2d:3h:4m:5s", returning 0 if s is not valid
+func parseColonDuration(s string) time.Duration {
+	var d time.Duration
+	for _, part := range strings.Split(strings.TrimSpace(s), ":") {
+		part = strings.TrimSpace(part)
+		if len(part) < 2 {
+			return 0
+		}
+		unit, ok := durationUnits[part[len(part)-1:]]
+		if !ok {
+			return 0
+		}
+		n, err := strconv.Atoi(part[:len(part)-1])
+		if err != nil {
+			return 0
+		}
+		d += time.Duration(n) * unit
+	}
+	return d
+}
+
+// parseNumberPrefix parses the number at the start of s, e.g. 2.5 in "2.5 Gb/s", and returns the rest of s
+// in lower case with spaces removed. ok is false if s does not start with a number.
+func parseNumberPrefix(s string) (n float64, rest string, ok bool) {
+	s = strings.ToLower(strings.Replace(s, " ", "", -1))
+	end := 0
+	for end < len(s) && (s[end] == '.' || (s[end] >= '0' && s[end] <= '9')) {
+		end++
+	}
+	n, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0, s, false
+	}
+	return n, s[end:], true
+}
+
+// parseUptime parses "2d:3h:4m:5s" as well as "2 days 3 hours 4 minutes 5 seconds", returning 0 if s is not valid
+func parseUptime(s string) time.Duration {
+	if d := parseColonDuration(s); d != 0 {
+		return d
+	}
+	var d time.Duration
+	fields := strings.Fields(strings.ToLower(strings.Replace(s, ",", " ", -1)))
+	for i := 0; i+1 < len(fields); i += 2 {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return 0
+		}
+		unit, ok := durationUnits[fields[i+1][:1]]
+		if !ok {
+			return 0
+		}
+		d += time.Duration(n) * unit
+	}
+	return d
+}
+
+// atoi converts s to an int, ignoring whitespace and returning 0 for anything that is not a number
+func atoi(s string) int {
+	i, _ := strconv.Atoi(strings.TrimSpace(s))
+	return i
+}
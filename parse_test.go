@@ -0,0 +1,66 @@
+package arubaos
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseColonDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"19m:0s", 19 * time.Minute},
+		{"2d:3h:4m:5s", 2*24*time.Hour + 3*time.Hour + 4*time.Minute + 5*time.Second},
+		{" 1h:0m:10s ", time.Hour + 10*time.Second},
+		{"", 0},
+		{"10", 0},
+		{"5x", 0},
+		{"am:5s", 0},
+	}
+	for _, tt := range tests {
+		if got := parseColonDuration(tt.in); got != tt.want {
+			t.Errorf("parseColonDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseUptime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"2d:3h:4m:5s", 2*24*time.Hour + 3*time.Hour + 4*time.Minute + 5*time.Second},
+		{"2 days 3 hours 4 minutes 5 seconds", 2*24*time.Hour + 3*time.Hour + 4*time.Minute + 5*time.Second},
+		{"1 hour, 10 minutes", time.Hour + 10*time.Minute},
+		{"unknown", 0},
+	}
+	for _, tt := range tests {
+		if got := parseUptime(tt.in); got != tt.want {
+			t.Errorf("parseUptime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFlexInt(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    flexInt
+		wantErr bool
+	}{
+		{`0`, 0, false},
+		{`42`, 42, false},
+		{`"42"`, 42, false},
+		{`""`, 0, false},
+		{`null`, 0, false},
+		{`"abc"`, 0, true},
+	}
+	for _, tt := range tests {
+		var got flexInt
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("flexInt %s = %d, %v", tt.in, got, err)
+		}
+	}
+}
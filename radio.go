@@ -0,0 +1,185 @@
+package arubaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ApRadio the RF state of one radio from "show ap radio-summary" and "show ap active"
+type ApRadio struct {
+	ApName  string
+	Group   string
+	IPAddr  string
+	Band    string // e.g. 2.4GHz, 5GHz or 6GHz
+	Mode    string // e.g. AP or AM
+	Channel string // as shown, e.g. 36E
+	// PrimaryChannel and BandwidthMHz are decoded from Channel
+	PrimaryChannel int
+	BandwidthMHz   int
+	EIRP           float64 // dBm
+	MaxEIRP        float64
+	NoiseFloor     int // dBm
+	ChannelUtil    int // percent
+	// Clients the clients on the AP in Band, "show ap active" counts clients per band so
+	// this is 0 when the AP has more than one radio in the band, e.g. dual 5GHz
+	Clients int
+}
+
+// RadioFilter selects the radios returned by GetApRadios, all fields are optional
+// and only one of ApName and Group can be set
+type RadioFilter struct {
+	ApName string
+	Group  string
+	Band   string // only return radios in this band, e.g. 5GHz
+}
+
+// parseChannel decodes the channel notation used by ArubaOS, e.g. 36E, 44+ or 1
+func parseChannel(s string) (primary, bandwidth int) {
+	s = strings.TrimSpace(s)
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	primary, _ = strconv.Atoi(s[:end])
+	switch strings.ToUpper(s[end:]) {
+	case "+", "-":
+		bandwidth = 40
+	case "E":
+		bandwidth = 80
+	case "S":
+		bandwidth = 160
+	case "":
+		bandwidth = 20
+	}
+	if primary == 0 {
+		bandwidth = 0
+	}
+	return primary, bandwidth
+}
+
+// splitSlash returns part i of a value like "18.0/24.0", or "" if there is no such part
+func splitSlash(s string, i int) string {
+	parts := strings.Split(s, "/")
+	if i >= len(parts) {
+		return ""
+	}
+	return strings.TrimSpace(parts[i])
+}
+
+// bandKey returns a normalised band, so "5GHz", "5 GHz" and "a" compare equal
+func bandKey(band string) string {
+	switch b := strings.ToLower(strings.Replace(band, " ", "", -1)); b {
+	case "g", "2.4ghz", "2.4":
+		return "2.4ghz"
+	case "a", "5ghz", "5":
+		return "5ghz"
+	case "6ghz", "6":
+		return "6ghz"
+	default:
+		return b
+	}
+}
+
+// GetApRadios returns the radios of every AP selected by f
+// This Command must be run from the Controller the APs are Registered with
+func (c *Client) GetApRadios(ctx context.Context, f RadioFilter) ([]ApRadio, error) {
+	if f.ApName != "" && f.Group != "" {
+		return nil, errors.New("only one of ap-name and group can be used as a filter")
+	}
+	suffix := ""
+	switch {
+	case f.ApName != "":
+		suffix = fmt.Sprintf(" ap-name %s", f.ApName)
+	case f.Group != "":
+		suffix = fmt.Sprintf(" ap-group %s", f.Group)
+	}
+	res, err := c.ShowCommand(ctx, "show ap radio-summary"+suffix)
+	if err != nil {
+		return nil, err
+	}
+	var radios []ApRadio
+	for _, name := range res.TableNames() {
+		rows, err := res.Rows(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			radios = append(radios, radioFromRow(row))
+		}
+	}
+	clients, err := c.getActiveClients(ctx, suffix)
+	if err != nil {
+		return nil, err
+	}
+	perBand := map[string]int{}
+	for _, radio := range radios {
+		perBand[radio.ApName+"/"+bandKey(radio.Band)]++
+	}
+	var selected []ApRadio
+	for _, radio := range radios {
+		key := radio.ApName + "/" + bandKey(radio.Band)
+		if perBand[key] == 1 {
+			radio.Clients = clients[key]
+		}
+		if f.Band == "" || bandKey(f.Band) == bandKey(radio.Band) {
+			selected = append(selected, radio)
+		}
+	}
+	return selected, nil
+}
+
+// radioFromRow decodes a row from "show ap radio-summary"
+func radioFromRow(row ShowRow) ApRadio {
+	radio := ApRadio{
+		ApName:  row.String("Name", "AP Name"),
+		Group:   row.String("Group"),
+		IPAddr:  row.String("IP Address", "IP-Address"),
+		Band:    row.String("Band"),
+		Mode:    row.String("Mode"),
+		Channel: row.String("Channel", "Ch"),
+	}
+	eirp := row.String("EIRP/MaxEIRP", "EIRP")
+	if chEirp := row.String("Ch/EIRP/MaxEIRP"); chEirp != "" {
+		radio.Channel = splitSlash(chEirp, 0)
+		eirp = strings.Join(strings.Split(chEirp, "/")[1:], "/")
+	}
+	radio.EIRP, _, _ = parseNumberPrefix(splitSlash(eirp, 0))
+	radio.MaxEIRP, _, _ = parseNumberPrefix(splitSlash(eirp, 1))
+	radio.PrimaryChannel, radio.BandwidthMHz = parseChannel(radio.Channel)
+	// NF/U/I is noise floor, channel utilization and interference
+	nfui := row.String("NF/U/I")
+	radio.NoiseFloor = atoi(row.String("Noise Floor", "NF"))
+	if radio.NoiseFloor == 0 {
+		radio.NoiseFloor = atoi(splitSlash(nfui, 0))
+	}
+	radio.ChannelUtil = atoi(strings.TrimSuffix(row.String("CU", "Channel Util", "Cu(%)"), "%"))
+	if radio.ChannelUtil == 0 {
+		radio.ChannelUtil = atoi(splitSlash(nfui, 1))
+	}
+	return radio
+}
+
+// getActiveClients returns the client count from "show ap active" keyed by "<ap name>/<band>"
+func (c *Client) getActiveClients(ctx context.Context, suffix string) (map[string]int, error) {
+	res, err := c.ShowCommand(ctx, "show ap active"+suffix)
+	if err != nil {
+		return nil, err
+	}
+	clients := map[string]int{}
+	for _, name := range res.TableNames() {
+		rows, err := res.Rows(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			ap := row.String("Name")
+			clients[ap+"/"+bandKey("2.4GHz")] = atoi(row.String("11g Clients", "2.4GHz Clients"))
+			clients[ap+"/"+bandKey("5GHz")] = atoi(row.String("11a Clients", "5GHz Clients"))
+			clients[ap+"/"+bandKey("6GHz")] = atoi(row.String("6GHz Clients"))
+		}
+	}
+	return clients, nil
+}
@@ -0,0 +1,25 @@
+package arubaos
+
+import "testing"
+
+func TestParseChannel(t *testing.T) {
+	tests := []struct {
+		in                 string
+		primary, bandwidth int
+	}{
+		{"1", 1, 20},
+		{"44+", 44, 40},
+		{"48-", 48, 40},
+		{"36E", 36, 80},
+		{"36S", 36, 160},
+		{" 149e ", 149, 80},
+		{"N/A", 0, 0},
+		{"", 0, 0},
+	}
+	for _, tt := range tests {
+		primary, bandwidth := parseChannel(tt.in)
+		if primary != tt.primary || bandwidth != tt.bandwidth {
+			t.Errorf("parseChannel(%q) = %d, %d, want %d, %d", tt.in, primary, bandwidth, tt.primary, tt.bandwidth)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package arubaos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RebootAp reboots an AP by Name, or by MacAddr if Name is empty, and returns the status from ArubaOS
+func (c *Client) RebootAp(ap AP) (string, error) {
+	return c.RebootApContext(context.Background(), ap)
+}
+
+// RebootApContext is like RebootAp but uses ctx for the request
+func (c *Client) RebootApContext(ctx context.Context, ap AP) (string, error) {
+	if !c.loggedIn() {
+		return "", ErrNotLoggedIn
+	}
+	var target ApTarget
+	switch {
+	case ap.Name != "":
+		target.Name = ap.Name
+	case ap.MacAddr != "":
+		target.WiredMac = ap.MacAddr
+	default:
+		return "", fmt.Errorf("%w: the AP has neither name nor MAC address", ErrInvalidTarget)
+	}
+	result, err := c.rebootTarget(ctx, target)
+	if result == nil {
+		return "", err
+	}
+	return strings.ToLower(result.StatusStr), err
+}
+
+// RebootApTarget reboots the AP selected by target with a single apboot request.
+// Only targets that select one AP are accepted, use RebootAps for groups and all APs
+// so they are rebooted in batches.
+func (c *Client) RebootApTarget(ctx context.Context, target ApTarget) error {
+	if !c.loggedIn() {
+		return ErrNotLoggedIn
+	}
+	if err := target.validate(); err != nil {
+		return err
+	}
+	if target.All || target.Group != "" {
+		return fmt.Errorf("%w: RebootApTarget reboots one AP, use RebootAps for %s", ErrInvalidTarget, target)
+	}
+	_, err := c.rebootTarget(ctx, target)
+	return err
+}
+
+// rebootTarget sends apboot for target
+func (c *Client) rebootTarget(ctx context.Context, target ApTarget) (*ObjectResult, error) {
+	return c.postObject(ctx, "/configuration/object/apboot", map[string]string{}, target.params())
+}
+
+// RebootOptions controls how RebootAps spreads the reboots over time
+type RebootOptions struct {
+	// BatchSize the number of APs rebooted before waiting Delay, 0 reboots all APs in one batch
+	BatchSize int
+	// MaxConcurrent the number of reboot requests sent at the same time within a batch, default 1
+	MaxConcurrent int
+	// Delay the time to wait between batches
+	Delay time.Duration
+}
+
+// RebootResult the outcome of rebooting one AP
+type RebootResult struct {
+	Target ApTarget // the target that selected the AP
+	AP     AP       // the entry in the AP database, empty if no AP matched
+	Err    error
+}
+
+// RebootAps does a rolling reboot of the APs selected by targets. Targets are looked up in
+// "show ap database long" and each matching AP is rebooted by its wired MAC, in batches as set
+// in opts, giving one result per AP. Targets without a match get a result with ErrApNotFound.
+// If ctx is cancelled the APs not yet rebooted get the error from ctx.
+func (c *Client) RebootAps(ctx context.Context, targets []ApTarget, opts RebootOptions) ([]RebootResult, error) {
+	for _, target := range targets {
+		if err := target.validate(); err != nil {
+			return nil, err
+		}
+	}
+	db, err := c.GetApDBContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []RebootResult
+	seen := map[string]bool{}
+	for _, target := range targets {
+		aps := target.resolve(db)
+		if len(aps) == 0 {
+			results = append(results, RebootResult{Target: target, Err: ErrApNotFound})
+			continue
+		}
+		for _, ap := range aps {
+			if mac := strings.ToLower(ap.MacAddr); !seen[mac] {
+				seen[mac] = true
+				results = append(results, RebootResult{Target: target, AP: ap})
+			}
+		}
+	}
+
+	var pending []*RebootResult
+	for i := range results {
+		if results[i].Err == nil {
+			pending = append(pending, &results[i])
+		}
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(pending)
+	}
+	workers := opts.MaxConcurrent
+	if workers <= 0 {
+		workers = 1
+	}
+	for start := 0; start < len(pending); start += batchSize {
+		if start > 0 && opts.Delay > 0 {
+			select {
+			case <-time.After(opts.Delay):
+			case <-ctx.Done():
+			}
+		}
+		end := start + batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		c.rebootBatch(ctx, pending[start:end], workers)
+	}
+	return results, nil
+}
+
+// rebootBatch reboots the APs in batch using up to workers requests at the same time
+func (c *Client) rebootBatch(ctx context.Context, batch []*RebootResult, workers int) {
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, result := range batch {
+		if err := ctx.Err(); err != nil {
+			result.Err = err
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(result *RebootResult) {
+			defer func() { <-sem; wg.Done() }()
+			_, result.Err = c.rebootTarget(ctx, ApTarget{WiredMac: result.AP.MacAddr})
+		}(result)
+	}
+	wg.Wait()
+}
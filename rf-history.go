@@ -0,0 +1,171 @@
+package arubaos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ArmReasons the meaning of the reason codes in "show ap arm history"
+var ArmReasons = map[string]string{
+	"I":    "Interference",
+	"R":    "Radar detection",
+	"N":    "Noise exceeded",
+	"E":    "Error threshold exceeded",
+	"INV":  "Invalid Channel",
+	"G":    "Rogue AP Containment",
+	"M":    "Empty Channel",
+	"P+":   "Rescan Power Increase",
+	"P-":   "Rescan Power Decrease",
+	"C+":   "Coverage Hole Power Increase",
+	"C-":   "Coverage Hole Power Decrease",
+	"OFF":  "Turn off Radio",
+	"ON":   "Turn on Radio",
+	"ARM":  "ARM assigned",
+	"AM":   "AirMatch assigned",
+	"DFS":  "DFS event",
+	"INIT": "Initial assignment",
+}
+
+// RFEvent a channel or power change on a radio
+type RFEvent struct {
+	Time       time.Time // zero if the timestamp could not be parsed, see TimeRaw
+	TimeRaw    string
+	ApName     string
+	Radio      string // the interface or band, e.g. 5GHz
+	OldChannel string
+	NewChannel string
+	OldPower   float64 // dBm
+	NewPower   float64
+	Reason     string
+	Source     string // arm or airmatch
+}
+
+// ReasonText returns the meaning of Reason, or Reason itself if the code is unknown
+func (e RFEvent) ReasonText() string {
+	if text, ok := ArmReasons[strings.TrimSpace(e.Reason)]; ok {
+		return text
+	}
+	return e.Reason
+}
+
+// rfTimeLayouts the timestamp formats used in ARM and AirMatch history
+var rfTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"Mon Jan _2 15:04:05 2006",
+	"Jan _2 15:04:05 2006",
+	"Jan _2 15:04:05",
+}
+
+// parseRFTime parses a history timestamp, timestamps without a year are placed in the
+// last 12 months relative to now
+func parseRFTime(s string, now time.Time) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range rfTimeLayouts {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			continue
+		}
+		if t.Year() == 0 {
+			t = t.AddDate(now.Year(), 0, 0)
+			if t.After(now) {
+				t = t.AddDate(-1, 0, 0)
+			}
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// GetArmHistory returns the channel and power changes made by ARM on an AP from "show ap arm history"
+// This Command must be run from the Controller the AP is Registered with
+func (c *Client) GetArmHistory(ctx context.Context, apName string) ([]RFEvent, error) {
+	return c.getRFEvents(ctx, fmt.Sprintf("show ap arm history ap-name %s", apName), apName, "arm")
+}
+
+// GetAirMatchHistory returns the channel and power changes made by AirMatch from "show airmatch history",
+// for all APs if apName is empty
+// This Command must be run from the Mobility Master
+func (c *Client) GetAirMatchHistory(ctx context.Context, apName string) ([]RFEvent, error) {
+	cmd := "show airmatch history"
+	if apName != "" {
+		cmd = fmt.Sprintf("%s ap-name %s", cmd, apName)
+	}
+	return c.getRFEvents(ctx, cmd, apName, "airmatch")
+}
+
+// getRFEvents runs a history command and decodes every table in the response
+func (c *Client) getRFEvents(ctx context.Context, cmd, apName, source string) ([]RFEvent, error) {
+	res, err := c.ShowCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var events []RFEvent
+	for _, name := range res.TableNames() {
+		rows, err := res.Rows(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			e := RFEvent{
+				TimeRaw:    row.String("Time of Change", "Timestamp", "Time"),
+				ApName:     row.String("AP Name", "AP", "Name"),
+				Radio:      row.String("Interface", "Radio", "Band"),
+				OldChannel: row.String("Old Channel", "Old Chan"),
+				NewChannel: row.String("New Channel", "New Chan"),
+				Reason:     row.String("Reason"),
+				Source:     source,
+			}
+			e.Time = parseRFTime(e.TimeRaw, now)
+			e.OldPower, _, _ = parseNumberPrefix(row.String("Old Power", "Old EIRP"))
+			e.NewPower, _, _ = parseNumberPrefix(row.String("New Power", "New EIRP"))
+			if e.ApName == "" {
+				e.ApName = apName
+			}
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// AirMatchAssignment the channel and power AirMatch has assigned to a radio
+type AirMatchAssignment struct {
+	ApName       string
+	Radio        string
+	Channel      string
+	BandwidthMHz int
+	EIRP         float64
+}
+
+// GetAirMatchSolution returns the current AirMatch solution from "show airmatch solution"
+// This Command must be run from the Mobility Master
+func (c *Client) GetAirMatchSolution(ctx context.Context) ([]AirMatchAssignment, error) {
+	res, err := c.ShowCommand(ctx, "show airmatch solution")
+	if err != nil {
+		return nil, err
+	}
+	var solution []AirMatchAssignment
+	for _, name := range res.TableNames() {
+		rows, err := res.Rows(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			a := AirMatchAssignment{
+				ApName:  row.String("AP Name", "AP", "Name"),
+				Radio:   row.String("Radio", "Band", "Interface"),
+				Channel: row.String("Channel", "Chan"),
+			}
+			_, a.BandwidthMHz = parseChannel(a.Channel)
+			if bw := atoi(strings.TrimSuffix(strings.ToUpper(row.String("Bandwidth", "BW")), "MHZ")); bw > 0 {
+				a.BandwidthMHz = bw
+			}
+			a.EIRP, _, _ = parseNumberPrefix(row.String("EIRP", "Power"))
+			solution = append(solution, a)
+		}
+	}
+	return solution, nil
+}
@@ -0,0 +1,173 @@
+package arubaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// globalResult the _global_result object ArubaOS adds to most responses
+type globalResult struct {
+	Status    flexInt `json:"status"` // a number or a string, depending on the endpoint
+	StatusStr string  `json:"status_str"`
+}
+
+// session returns the current session cookie and UIDARUBA token
+func (c *Client) session() (*http.Cookie, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cookie, c.uidAruba
+}
+
+// setSession stores a new session, use nil and "" to clear it
+func (c *Client) setSession(cookie *http.Cookie, uidAruba string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cookie = cookie
+	c.uidAruba = uidAruba
+}
+
+// loggedIn returns true if Login has been called successfully
+func (c *Client) loggedIn() bool {
+	cookie, _ := c.session()
+	return cookie != nil
+}
+
+// authorize adds the current session to req and returns the UIDARUBA token that was used
+func (c *Client) authorize(req *http.Request) string {
+	cookie, uid := c.session()
+	req.Header.Del("Cookie")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	q := req.URL.Query()
+	q.Set("UIDARUBA", uid)
+	req.URL.RawQuery = q.Encode()
+	return uid
+}
+
+// do sends req with the current session and returns the response body.
+// If the controller tells us that the session is no longer valid we login
+// again and replay req once. The body is also returned together with an
+// *APIError so callers can look at the details of a failed request.
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	uid := c.authorize(req)
+	res, body, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.check(req, res, body); !errors.Is(err, ErrSessionExpired) {
+		return body, err
+	}
+	if err = c.reauth(req.Context(), uid); err != nil {
+		return nil, err
+	}
+	if req, err = rewind(req); err != nil {
+		return nil, err
+	}
+	c.authorize(req)
+	if res, body, err = c.send(req); err != nil {
+		return nil, err
+	}
+	return body, c.check(req, res, body)
+}
+
+// send performs req and reads the whole response body
+func (c *Client) send(req *http.Request) (res *http.Response, body []byte, err error) {
+	info := c.requestInfo(req)
+	req = c.beforeRequest(req, info)
+	start := time.Now()
+	defer func() { c.afterResponse(req.Context(), info, start, res, body, err) }()
+	c.logRequest(req)
+	res, err = c.http.Do(req)
+	if err != nil {
+		return nil, nil, &TransportError{Endpoint: info.Endpoint, Err: redactURLError(err)}
+	}
+	defer res.Body.Close()
+	body, err = io.ReadAll(res.Body)
+	if err != nil {
+		return res, nil, &TransportError{Endpoint: info.Endpoint, Err: redactURLError(err)}
+	}
+	c.logResponse(req, res, body)
+	return res, body, nil
+}
+
+// reauth logs in again unless another request already did so after uid was used
+func (c *Client) reauth(ctx context.Context, uid string) error {
+	c.reauthMu.Lock()
+	defer c.reauthMu.Unlock()
+	if _, current := c.session(); current != "" && current != uid {
+		return nil
+	}
+	c.logf(LogWarn, "session expired, logging in again")
+	err := c.LoginContext(ctx)
+	if err != nil {
+		c.logf(LogError, "login after expired session failed: %v", err)
+	}
+	if c.OnReauth != nil {
+		c.OnReauth(err)
+	}
+	return err
+}
+
+// rewind returns a copy of req with a fresh body so it can be sent again
+func rewind(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body can not be replayed")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
+
+// check returns an *APIError if the response to req is an error. It also looks for
+// the different ways ArubaOS tells us that the session is gone: HTTP 401, a redirect
+// to the login page or, for GETs only, an invalid session error in _global_result.
+// Writes are not matched on status_str as they are not safe to replay and their
+// errors may be about session ACLs or authentication profiles.
+func (c *Client) check(req *http.Request, res *http.Response, body []byte) error {
+	apiErr := &APIError{HTTPStatus: res.StatusCode, Endpoint: c.endpoint(req), Command: c.command(req)}
+	var result struct {
+		GlobalRes *globalResult `json:"_global_result"`
+	}
+	if err := json.Unmarshal(body, &result); err == nil && result.GlobalRes != nil {
+		apiErr.Status = int(result.GlobalRes.Status)
+		apiErr.StatusStr = result.GlobalRes.StatusStr
+	}
+	switch final := res.Request; {
+	case res.StatusCode == http.StatusUnauthorized:
+		apiErr.expired = true
+	case final != nil && final.URL.Path != req.URL.Path && strings.Contains(strings.ToLower(final.URL.Path), "login"):
+		apiErr.expired = true
+		apiErr.StatusStr = "redirected to " + final.URL.Path
+	case apiErr.Status != 0:
+		apiErr.expired = req.Method == http.MethodGet && isSessionError(apiErr.StatusStr)
+	case res.StatusCode >= http.StatusBadRequest:
+	default:
+		return nil
+	}
+	return apiErr
+}
+
+// isSessionError returns true if a status_str says that the session or UIDARUBA token is not valid
+func isSessionError(statusStr string) bool {
+	s := strings.ToLower(statusStr)
+	for _, word := range []string{"invalid session", "session expired", "session timed out", "session timeout",
+		"invalid uidaruba", "uidaruba missing", "not logged in"} {
+		if strings.Contains(s, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package arubaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeController answers /api/login and passes every other request to handler,
+// valid tells if the request has the UIDARUBA token of the current session
+type fakeController struct {
+	mu      sync.Mutex
+	logins  int
+	uid     string
+	calls   int
+	handler func(w http.ResponseWriter, r *http.Request, valid bool)
+	srv     *httptest.Server
+}
+
+// newFakeController starts a fake controller and returns a Client that is logged in to it, call Close when done
+func newFakeController(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, valid bool)) (*Client, *fakeController) {
+	t.Helper()
+	f := &fakeController{handler: handler}
+	f.srv = httptest.NewServer(f)
+	c := &Client{BaseURL: f.srv.URL + "/v1", http: f.srv.Client()}
+	if err := c.Login(); err != nil {
+		f.Close()
+		t.Fatal(err)
+	}
+	return c, f
+}
+
+// Close stops the fake controller
+func (f *fakeController) Close() {
+	f.srv.Close()
+}
+
+func (f *fakeController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	if r.URL.Path == "/v1/api/login" {
+		f.logins++
+		f.uid = fmt.Sprintf("uid%d", f.logins)
+		uid := f.uid
+		f.mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: "SESSION", Value: uid})
+		fmt.Fprintf(w, `{"_global_result":{"status":"0","status_str":"ok","UIDARUBA":"%s"}}`, uid)
+		return
+	}
+	f.calls++
+	valid := r.URL.Query().Get("UIDARUBA") == f.uid
+	f.mu.Unlock()
+	f.handler(w, r, valid)
+}
+
+// expire invalidates the current session
+func (f *fakeController) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.uid = "expired"
+}
+
+// counts returns the number of logins and other requests
+func (f *fakeController) counts() (logins, calls int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.logins, f.calls
+}
+
+func TestDoReplaysAfterUnauthorized(t *testing.T) {
+	c, f := newFakeController(t, func(w http.ResponseWriter, r *http.Request, valid bool) {
+		if !valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"AP Database":[{"Name":"ap01"}]}`)
+	})
+	defer f.Close()
+	f.expire()
+	var reauthErr = errors.New("OnReauth not called")
+	c.OnReauth = func(err error) { reauthErr = err }
+	aps, err := c.GetApDB()
+	if err != nil || len(aps) != 1 {
+		t.Fatalf("GetApDB() = %v, %v", aps, err)
+	}
+	if logins, calls := f.counts(); logins != 2 || calls != 2 {
+		t.Errorf("logins = %d, calls = %d, want 2 and 2", logins, calls)
+	}
+	if reauthErr != nil {
+		t.Errorf("OnReauth error = %v", reauthErr)
+	}
+}
+
+func TestDoDetectsLoginRedirect(t *testing.T) {
+	c, f := newFakeController(t, func(w http.ResponseWriter, r *http.Request, valid bool) {
+		switch {
+		case r.URL.Path == "/v1/login.html":
+			fmt.Fprint(w, "<html>login</html>")
+		case !valid:
+			http.Redirect(w, r, "/v1/login.html", http.StatusFound)
+		default:
+			fmt.Fprint(w, `{"AP Database":[{"Name":"ap01"}]}`)
+		}
+	})
+	defer f.Close()
+	f.expire()
+	aps, err := c.GetApDB()
+	if err != nil || len(aps) != 1 {
+		t.Fatalf("GetApDB() = %v, %v", aps, err)
+	}
+	if logins, _ := f.counts(); logins != 2 {
+		t.Errorf("logins = %d, want 2", logins)
+	}
+}
+
+func TestDoReauthOnSessionStatusForGet(t *testing.T) {
+	c, f := newFakeController(t, func(w http.ResponseWriter, r *http.Request, valid bool) {
+		if !valid {
+			fmt.Fprint(w, `{"_global_result":{"status":1,"status_str":"Invalid session id"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"AP Database":[{"Name":"ap01"}]}`)
+	})
+	defer f.Close()
+	f.expire()
+	if _, err := c.GetApDB(); err != nil {
+		t.Fatalf("GetApDB() error = %v", err)
+	}
+	if logins, calls := f.counts(); logins != 2 || calls != 2 {
+		t.Errorf("logins = %d, calls = %d, want 2 and 2", logins, calls)
+	}
+}
+
+func TestDoDoesNotReplayPost(t *testing.T) {
+	c, f := newFakeController(t, func(w http.ResponseWriter, r *http.Request, valid bool) {
+		fmt.Fprint(w, `{"_global_result":{"status":1,"status_str":"Invalid session id"}}`)
+	})
+	defer f.Close()
+	_, err := c.PostObjects(context.Background(), "/md", []ObjectAction{{Name: "ap_group", Data: ProfileRef{Name: "g1"}}})
+	var objErr *ObjectError
+	if !errors.As(err, &objErr) || errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("PostObjects() error = %v, want an *ObjectError that is not ErrSessionExpired", err)
+	}
+	if logins, calls := f.counts(); logins != 1 || calls != 1 {
+		t.Errorf("logins = %d, calls = %d, want 1 and 1", logins, calls)
+	}
+}
+
+func TestDoConcurrentReauthLogsInOnce(t *testing.T) {
+	c, f := newFakeController(t, func(w http.ResponseWriter, r *http.Request, valid bool) {
+		if !valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"AP Database":[]}`)
+	})
+	defer f.Close()
+	f.expire()
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetApDB()
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetApDB() error = %v", err)
+		}
+	}
+	if logins, _ := f.counts(); logins != 2 {
+		t.Errorf("logins = %d, want 2", logins)
+	}
+}
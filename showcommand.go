@@ -0,0 +1,106 @@
+package arubaos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ShowResult the response from running a show command through /configuration/showcommand
+type ShowResult struct {
+	Command string
+	// Raw holds every key in the response, tables are named after their CLI title, e.g. "AP Database"
+	Raw map[string]json.RawMessage
+	// Meta the column names from _meta
+	Meta []string
+	// Data the text lines from _data, used by commands that has no table output
+	Data []string
+}
+
+// ShowRow one row of a table, keyed by column name
+type ShowRow map[string]interface{}
+
+// String returns the value of the first column in columns that is present and not empty,
+// values that are not strings are formatted with fmt.Sprint
+func (r ShowRow) String(columns ...string) string {
+	for _, col := range columns {
+		switch v := r[col].(type) {
+		case nil:
+			continue
+		case string:
+			if v != "" {
+				return v
+			}
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// ShowCommand runs a CLI show command, e.g. "show ap database long", and returns the decoded response
+func (c *Client) ShowCommand(ctx context.Context, cmd string) (*ShowResult, error) {
+	return c.showCommand(ctx, cmd, map[string]string{})
+}
+
+// showCommand runs cmd with extra query values in qs, e.g. config_path
+func (c *Client) showCommand(ctx context.Context, cmd string, qs map[string]string) (*ShowResult, error) {
+	if !c.loggedIn() {
+		return nil, ErrNotLoggedIn
+	}
+	req, err := c.genGetReq(ctx, "/configuration/showcommand")
+	if err != nil {
+		return nil, err
+	}
+	qs["command"] = cmd
+	c.updateReq(req, qs)
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	result := &ShowResult{Command: cmd}
+	if err = c.decode(req, resp, &result.Raw); err != nil {
+		return nil, err
+	}
+	if meta, ok := result.Raw["_meta"]; ok {
+		json.Unmarshal(meta, &result.Meta)
+	}
+	if data, ok := result.Raw["_data"]; ok {
+		json.Unmarshal(data, &result.Data)
+	}
+	return result, nil
+}
+
+// TableNames returns the names of all tables in the response, sorted
+func (r *ShowResult) TableNames() []string {
+	var names []string
+	for name := range r.Raw {
+		if !strings.HasPrefix(name, "_") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DecodeTable decodes the rows of the table called name into v, which should be a pointer
+// to a slice of structs with json tags matching the column names. A missing table leaves v untouched.
+func (r *ShowResult) DecodeTable(name string, v interface{}) error {
+	table, ok := r.Raw[name]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(table, v); err != nil {
+		return &DecodeError{Endpoint: "/configuration/showcommand", Command: r.Command, Err: err}
+	}
+	return nil
+}
+
+// Rows returns the rows of the table called name
+func (r *ShowResult) Rows(name string) ([]ShowRow, error) {
+	var rows []ShowRow
+	err := r.DecodeTable(name, &rows)
+	return rows, err
+}
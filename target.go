@@ -0,0 +1,91 @@
+package arubaos
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidTarget is returned when an ApTarget does not select exactly one kind of target
+	ErrInvalidTarget = errors.New("exactly one of wired-mac, ap-name, ip-addr, ap-group or all must be set")
+	// ErrApNotFound is returned when no AP in the AP database matches a target
+	ErrApNotFound = errors.New("no matching AP in the AP database")
+)
+
+// ApTarget selects the APs an action applies to, exactly one field must be set
+type ApTarget struct {
+	WiredMac string
+	Name     string
+	IPAddr   string
+	Group    string
+	All      bool
+}
+
+// String returns the target as it would be written in the CLI, e.g. "ap-name ap01"
+func (t ApTarget) String() string {
+	for key, val := range t.params() {
+		if val == true {
+			return key
+		}
+		return fmt.Sprintf("%s %v", key, val)
+	}
+	return ""
+}
+
+// validate returns ErrInvalidTarget unless exactly one field is set
+func (t ApTarget) validate() error {
+	if len(t.params()) != 1 {
+		return fmt.Errorf("%w: %+v", ErrInvalidTarget, t)
+	}
+	return nil
+}
+
+// params returns the fields that are set, keyed by their ArubaOS parameter name
+func (t ApTarget) params() map[string]interface{} {
+	params := map[string]interface{}{}
+	if t.WiredMac != "" {
+		params["wired-mac"] = t.WiredMac
+	}
+	if t.Name != "" {
+		params["ap-name"] = t.Name
+	}
+	if t.IPAddr != "" {
+		params["ip-addr"] = t.IPAddr
+	}
+	if t.Group != "" {
+		params["ap-group"] = t.Group
+	}
+	if t.All {
+		params["all"] = true
+	}
+	return params
+}
+
+// matches returns true if ap is selected by t
+func (t ApTarget) matches(ap AP) bool {
+	switch {
+	case t.WiredMac != "":
+		return strings.EqualFold(ap.MacAddr, t.WiredMac)
+	case t.Name != "":
+		return ap.Name == t.Name
+	case t.IPAddr != "":
+		return ap.IPAddr == t.IPAddr
+	case t.Group != "":
+		return ap.Group == t.Group
+	case t.All:
+		return true
+	}
+	return false
+}
+
+// resolve returns the APs in db that are selected by t
+func (t ApTarget) resolve(db []AP) []AP {
+	var aps []AP
+	for _, ap := range db {
+		if t.matches(ap) {
+			aps = append(aps, ap)
+		}
+	}
+	return aps
+}
@@ -0,0 +1,69 @@
+package arubaos
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestInfo describes a request sent by the Client, it is passed to BeforeRequest
+type RequestInfo struct {
+	Method     string
+	Endpoint   string // relative to BaseURL, e.g. /configuration/showcommand
+	Command    string // the show command, if any
+	Object     string // the configuration object for /configuration/object/<name>, if any
+	ConfigPath string // config_path, if any
+	BytesSent  int64  // size of the request body, -1 if unknown
+}
+
+// ResponseInfo describes the outcome of a request, it is passed to AfterResponse
+type ResponseInfo struct {
+	RequestInfo
+	StatusCode    int // HTTP status, 0 if no response was received
+	Duration      time.Duration
+	BytesReceived int
+	Err           error // a *TransportError if no response was received
+}
+
+// requestInfo returns the description of req for the tracing hooks
+func (c *Client) requestInfo(req *http.Request) RequestInfo {
+	q := req.URL.Query()
+	info := RequestInfo{
+		Method:     req.Method,
+		Endpoint:   c.endpoint(req),
+		Command:    q.Get("command"),
+		ConfigPath: q.Get("config_path"),
+		BytesSent:  req.ContentLength,
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		info.BytesSent = 0
+	}
+	if strings.HasPrefix(info.Endpoint, "/configuration/object/") {
+		info.Object = strings.TrimPrefix(info.Endpoint, "/configuration/object/")
+	}
+	return info
+}
+
+// beforeRequest calls BeforeRequest and returns req with the context it returned
+func (c *Client) beforeRequest(req *http.Request, info RequestInfo) *http.Request {
+	if c.BeforeRequest == nil {
+		return req
+	}
+	if ctx := c.BeforeRequest(req.Context(), info); ctx != nil && ctx != req.Context() {
+		return req.WithContext(ctx)
+	}
+	return req
+}
+
+// afterResponse calls AfterResponse with the outcome of req
+func (c *Client) afterResponse(ctx context.Context, info RequestInfo, start time.Time, res *http.Response, body []byte, err error) {
+	if c.AfterResponse == nil {
+		return
+	}
+	out := ResponseInfo{RequestInfo: info, Duration: time.Since(start), BytesReceived: len(body), Err: err}
+	if res != nil {
+		out.StatusCode = res.StatusCode
+	}
+	c.AfterResponse(ctx, out)
+}
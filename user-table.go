@@ -0,0 +1,137 @@
+package arubaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUserNotFound is returned by GetUser when the MAC address is not in the user table
+var ErrUserNotFound = errors.New("user not found in user-table")
+
+// APAssoc show user-table
+type APAssoc struct {
+	Users []User `json:"Users"`
+}
+
+// User one entry in "show user-table"
+type User struct {
+	APName        string `json:"AP name"`
+	AgeDHM        string `json:"Age(d:h:m)"`
+	Auth          string `json:"Auth"`
+	EssidBssidPhy string `json:"Essid/Bssid/Phy"`
+	Essid         string `json:"-"`
+	Bssid         string `json:"-"`
+	Phy           string `json:"-"`
+	ForwardMode   string `json:"Forward mode"`
+	HostName      string `json:"Host Name"`
+	IP            string `json:"IP"`
+	MAC           string `json:"MAC"`
+	Name          string `json:"Name"`
+	Profile       string `json:"Profile"`
+	Roaming       string `json:"Roaming"`
+	Role          string `json:"Role"`
+	Type          string `json:"Type"`
+	UserType      string `json:"User Type"`
+	VPNLink       string `json:"VPN link"`
+}
+
+// UnmarshalJSON accepts null and non-string values in any column and splits Essid/Bssid/Phy
+func (u *User) UnmarshalJSON(b []byte) error {
+	var row ShowRow
+	if err := json.Unmarshal(b, &row); err != nil {
+		return err
+	}
+	*u = User{
+		APName:        row.String("AP name"),
+		AgeDHM:        row.String("Age(d:h:m)"),
+		Auth:          row.String("Auth"),
+		EssidBssidPhy: row.String("Essid/Bssid/Phy"),
+		ForwardMode:   row.String("Forward mode"),
+		HostName:      row.String("Host Name"),
+		IP:            row.String("IP"),
+		MAC:           row.String("MAC"),
+		Name:          row.String("Name"),
+		Profile:       row.String("Profile"),
+		Roaming:       row.String("Roaming"),
+		Role:          row.String("Role"),
+		Type:          row.String("Type"),
+		UserType:      row.String("User Type"),
+		VPNLink:       row.String("VPN link"),
+	}
+	u.Essid, u.Bssid, u.Phy = splitEssidBssidPhy(u.EssidBssidPhy)
+	return nil
+}
+
+// splitEssidBssidPhy splits "guest/a8:bd:27:5e:a8:12/a-VHT", the ESSID itself may contain /
+func splitEssidBssidPhy(s string) (essid, bssid, phy string) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 3 {
+		return s, "", ""
+	}
+	n := len(parts)
+	return strings.Join(parts[:n-2], "/"), parts[n-2], parts[n-1]
+}
+
+// UserFilter selects users in GetUserTable, at most one field can be set
+type UserFilter struct {
+	Role   string
+	ApName string
+	Essid  string
+	IP     string
+}
+
+// command returns the show user-table command for f
+func (f UserFilter) command() (string, error) {
+	cmd := "show user-table"
+	set := 0
+	for _, kv := range [][2]string{{"role", f.Role}, {"ap-name", f.ApName}, {"essid", f.Essid}, {"ip", f.IP}} {
+		if kv[1] != "" {
+			cmd = fmt.Sprintf("show user-table %s %s", kv[0], kv[1])
+			set++
+		}
+	}
+	if set > 1 {
+		return "", errors.New("only one of role, ap-name, essid or ip can be used as a filter")
+	}
+	return cmd, nil
+}
+
+// GetUserTable returns the users from "show user-table", optionally filtered
+// This Command must be run from the Controller the users are on
+func (c *Client) GetUserTable(ctx context.Context, f UserFilter) ([]User, error) {
+	cmd, err := f.command()
+	if err != nil {
+		return nil, err
+	}
+	return c.getUsers(ctx, cmd)
+}
+
+// GetUser (show user-table mac <mac-addr>)
+func (c *Client) GetUser(ctx context.Context, mac string) (User, error) {
+	users, err := c.getUsers(ctx, fmt.Sprintf("show user-table mac %s", mac))
+	if err != nil {
+		return User{}, err
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.MAC, mac) {
+			return user, nil
+		}
+	}
+	return User{}, ErrUserNotFound
+}
+
+// getUsers runs a show user-table command
+func (c *Client) getUsers(ctx context.Context, cmd string) ([]User, error) {
+	res, err := c.ShowCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	var assoc APAssoc
+	if err = res.DecodeTable("Users", &assoc.Users); err != nil {
+		return nil, err
+	}
+	return assoc.Users, nil
+}
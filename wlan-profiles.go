@@ -0,0 +1,408 @@
+package arubaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNotApplied is returned when reading back a profile after a write shows other values than were written
+var ErrNotApplied = errors.New("configuration was not applied")
+
+// SSIDProfile the ssid_prof configuration object
+type SSIDProfile struct {
+	Name          string
+	ESSID         string
+	OpMode        string // e.g. opensystem, wpa2-aes, wpa2-psk-aes, wpa3-sae-aes
+	WPAPassphrase string // not returned when reading the profile
+}
+
+// ssidProfileWire ssid_prof as sent and received by ArubaOS
+type ssidProfileWire struct {
+	Name  string `json:"profile-name"`
+	ESSID *struct {
+		ESSID string `json:"essid"`
+	} `json:"essid,omitempty"`
+	OpMode *struct {
+		Name string `json:"name"`
+	} `json:"opmode,omitempty"`
+	WPAPassphrase *struct {
+		Passphrase string `json:"wpa-passphrase"`
+	} `json:"wpa_passphrase,omitempty"`
+}
+
+// MarshalJSON encodes the profile in the ArubaOS format
+func (p SSIDProfile) MarshalJSON() ([]byte, error) {
+	w := ssidProfileWire{Name: p.Name}
+	if p.ESSID != "" {
+		w.ESSID = &struct {
+			ESSID string `json:"essid"`
+		}{p.ESSID}
+	}
+	if p.OpMode != "" {
+		w.OpMode = &struct {
+			Name string `json:"name"`
+		}{p.OpMode}
+	}
+	if p.WPAPassphrase != "" {
+		w.WPAPassphrase = &struct {
+			Passphrase string `json:"wpa-passphrase"`
+		}{p.WPAPassphrase}
+	}
+	return json.Marshal(w)
+}
+
+// UnmarshalJSON decodes the profile from the ArubaOS format
+func (p *SSIDProfile) UnmarshalJSON(b []byte) error {
+	var w ssidProfileWire
+	if err := json.Unmarshal(b, &w); err != nil {
+		return err
+	}
+	*p = SSIDProfile{Name: w.Name}
+	if w.ESSID != nil {
+		p.ESSID = w.ESSID.ESSID
+	}
+	if w.OpMode != nil {
+		p.OpMode = w.OpMode.Name
+	}
+	if w.WPAPassphrase != nil {
+		p.WPAPassphrase = w.WPAPassphrase.Passphrase
+	}
+	return nil
+}
+
+// applied returns true if got has the values set in p, the passphrase is not compared
+func (p SSIDProfile) applied(got SSIDProfile) bool {
+	return (p.ESSID == "" || p.ESSID == got.ESSID) && (p.OpMode == "" || p.OpMode == got.OpMode)
+}
+
+// VirtualAp the virtual_ap configuration object
+type VirtualAp struct {
+	Name        string
+	SSIDProfile string
+	AAAProfile  string
+	VLAN        string
+	ForwardMode string // e.g. tunnel, bridge, decrypt-tunnel
+}
+
+// virtualApWire virtual_ap as sent and received by ArubaOS
+type virtualApWire struct {
+	Name        string      `json:"profile-name"`
+	SSIDProfile *ProfileRef `json:"ssid_prof,omitempty"`
+	AAAProfile  *ProfileRef `json:"aaa_prof,omitempty"`
+	VLAN        *struct {
+		VLAN string `json:"vlan"`
+	} `json:"vlan,omitempty"`
+	ForwardMode *struct {
+		ForwardMode string `json:"forward-mode"`
+	} `json:"forward_mode,omitempty"`
+}
+
+// MarshalJSON encodes the profile in the ArubaOS format
+func (v VirtualAp) MarshalJSON() ([]byte, error) {
+	w := virtualApWire{Name: v.Name}
+	if v.SSIDProfile != "" {
+		w.SSIDProfile = &ProfileRef{Name: v.SSIDProfile}
+	}
+	if v.AAAProfile != "" {
+		w.AAAProfile = &ProfileRef{Name: v.AAAProfile}
+	}
+	if v.VLAN != "" {
+		w.VLAN = &struct {
+			VLAN string `json:"vlan"`
+		}{v.VLAN}
+	}
+	if v.ForwardMode != "" {
+		w.ForwardMode = &struct {
+			ForwardMode string `json:"forward-mode"`
+		}{v.ForwardMode}
+	}
+	return json.Marshal(w)
+}
+
+// UnmarshalJSON decodes the profile from the ArubaOS format
+func (v *VirtualAp) UnmarshalJSON(b []byte) error {
+	var w virtualApWire
+	if err := json.Unmarshal(b, &w); err != nil {
+		return err
+	}
+	*v = VirtualAp{Name: w.Name}
+	if w.SSIDProfile != nil {
+		v.SSIDProfile = w.SSIDProfile.Name
+	}
+	if w.AAAProfile != nil {
+		v.AAAProfile = w.AAAProfile.Name
+	}
+	if w.VLAN != nil {
+		v.VLAN = w.VLAN.VLAN
+	}
+	if w.ForwardMode != nil {
+		v.ForwardMode = w.ForwardMode.ForwardMode
+	}
+	return nil
+}
+
+// applied returns true if got has the values set in v
+func (v VirtualAp) applied(got VirtualAp) bool {
+	return (v.SSIDProfile == "" || v.SSIDProfile == got.SSIDProfile) &&
+		(v.AAAProfile == "" || v.AAAProfile == got.AAAProfile) &&
+		(v.VLAN == "" || v.VLAN == got.VLAN) &&
+		(v.ForwardMode == "" || v.ForwardMode == got.ForwardMode)
+}
+
+// AAAProfile the aaa_prof configuration object
+type AAAProfile struct {
+	Name             string
+	InitialRole      string
+	DefaultUserRole  string // role after 802.1X authentication
+	Dot1xAuthProfile string
+	Dot1xServerGroup string
+}
+
+// aaaProfileWire aaa_prof as sent and received by ArubaOS
+type aaaProfileWire struct {
+	Name        string `json:"profile-name"`
+	InitialRole *struct {
+		Role string `json:"role"`
+	} `json:"initial_role,omitempty"`
+	DefaultUserRole *struct {
+		Role string `json:"role"`
+	} `json:"dot1x_default_role,omitempty"`
+	Dot1xAuthProfile *ProfileRef `json:"dot1x_auth_profile,omitempty"`
+	Dot1xServerGroup *struct {
+		ServerGroup string `json:"srv-group"`
+	} `json:"dot1x_server_group,omitempty"`
+}
+
+// MarshalJSON encodes the profile in the ArubaOS format
+func (a AAAProfile) MarshalJSON() ([]byte, error) {
+	w := aaaProfileWire{Name: a.Name}
+	if a.InitialRole != "" {
+		w.InitialRole = &struct {
+			Role string `json:"role"`
+		}{a.InitialRole}
+	}
+	if a.DefaultUserRole != "" {
+		w.DefaultUserRole = &struct {
+			Role string `json:"role"`
+		}{a.DefaultUserRole}
+	}
+	if a.Dot1xAuthProfile != "" {
+		w.Dot1xAuthProfile = &ProfileRef{Name: a.Dot1xAuthProfile}
+	}
+	if a.Dot1xServerGroup != "" {
+		w.Dot1xServerGroup = &struct {
+			ServerGroup string `json:"srv-group"`
+		}{a.Dot1xServerGroup}
+	}
+	return json.Marshal(w)
+}
+
+// UnmarshalJSON decodes the profile from the ArubaOS format
+func (a *AAAProfile) UnmarshalJSON(b []byte) error {
+	var w aaaProfileWire
+	if err := json.Unmarshal(b, &w); err != nil {
+		return err
+	}
+	*a = AAAProfile{Name: w.Name}
+	if w.InitialRole != nil {
+		a.InitialRole = w.InitialRole.Role
+	}
+	if w.DefaultUserRole != nil {
+		a.DefaultUserRole = w.DefaultUserRole.Role
+	}
+	if w.Dot1xAuthProfile != nil {
+		a.Dot1xAuthProfile = w.Dot1xAuthProfile.Name
+	}
+	if w.Dot1xServerGroup != nil {
+		a.Dot1xServerGroup = w.Dot1xServerGroup.ServerGroup
+	}
+	return nil
+}
+
+// applied returns true if got has the values set in a
+func (a AAAProfile) applied(got AAAProfile) bool {
+	return (a.InitialRole == "" || a.InitialRole == got.InitialRole) &&
+		(a.DefaultUserRole == "" || a.DefaultUserRole == got.DefaultUserRole) &&
+		(a.Dot1xAuthProfile == "" || a.Dot1xAuthProfile == got.Dot1xAuthProfile) &&
+		(a.Dot1xServerGroup == "" || a.Dot1xServerGroup == got.Dot1xServerGroup)
+}
+
+// getProfiles reads the profiles of type object at configPath into v, only the profile called name if name is set
+func (c *Client) getProfiles(ctx context.Context, object, configPath, name string, v interface{}) error {
+	var filters []ObjectFilter
+	if name != "" {
+		filters = []ObjectFilter{{Field: object + ".profile-name", Op: FilterEq, Values: []string{name}}}
+	}
+	data, err := c.GetObject(ctx, object, configPath, filters)
+	if err != nil {
+		return err
+	}
+	return data.Decode(v)
+}
+
+// deleteProfile deletes the profile of type object called name at configPath
+func (c *Client) deleteProfile(ctx context.Context, object, configPath, name string) error {
+	action := ObjectAction{Name: object, Action: ActionDelete, Data: ProfileRef{Name: name}}
+	_, err := c.PostObjects(ctx, configPath, []ObjectAction{action})
+	return err
+}
+
+// ListSSIDProfiles returns all ssid_prof at configPath
+func (c *Client) ListSSIDProfiles(ctx context.Context, configPath string) ([]SSIDProfile, error) {
+	var profiles []SSIDProfile
+	err := c.getProfiles(ctx, "ssid_prof", configPath, "", &profiles)
+	return profiles, err
+}
+
+// GetSSIDProfile returns the ssid_prof called name at configPath, ErrObjectNotFound if it does not exist
+func (c *Client) GetSSIDProfile(ctx context.Context, configPath, name string) (SSIDProfile, error) {
+	var profiles []SSIDProfile
+	if err := c.getProfiles(ctx, "ssid_prof", configPath, name, &profiles); err != nil {
+		return SSIDProfile{}, err
+	}
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return SSIDProfile{}, fmt.Errorf("ssid_prof %s: %w", name, ErrObjectNotFound)
+}
+
+// PutSSIDProfile creates or updates an ssid_prof at configPath and reads it back,
+// returning ErrNotApplied if the profile does not have the values that were written
+func (c *Client) PutSSIDProfile(ctx context.Context, configPath string, p SSIDProfile) error {
+	if _, err := c.PostObjects(ctx, configPath, []ObjectAction{{Name: "ssid_prof", Data: p}}); err != nil {
+		return err
+	}
+	got, err := c.GetSSIDProfile(ctx, configPath, p.Name)
+	if err != nil {
+		return err
+	}
+	if !p.applied(got) {
+		return fmt.Errorf("ssid_prof %s: %w", p.Name, ErrNotApplied)
+	}
+	return nil
+}
+
+// DeleteSSIDProfile deletes the ssid_prof called name at configPath
+func (c *Client) DeleteSSIDProfile(ctx context.Context, configPath, name string) error {
+	return c.deleteProfile(ctx, "ssid_prof", configPath, name)
+}
+
+// ListVirtualAps returns all virtual_ap at configPath
+func (c *Client) ListVirtualAps(ctx context.Context, configPath string) ([]VirtualAp, error) {
+	var vaps []VirtualAp
+	err := c.getProfiles(ctx, "virtual_ap", configPath, "", &vaps)
+	return vaps, err
+}
+
+// GetVirtualAp returns the virtual_ap called name at configPath, ErrObjectNotFound if it does not exist
+func (c *Client) GetVirtualAp(ctx context.Context, configPath, name string) (VirtualAp, error) {
+	var vaps []VirtualAp
+	if err := c.getProfiles(ctx, "virtual_ap", configPath, name, &vaps); err != nil {
+		return VirtualAp{}, err
+	}
+	for _, v := range vaps {
+		if v.Name == name {
+			return v, nil
+		}
+	}
+	return VirtualAp{}, fmt.Errorf("virtual_ap %s: %w", name, ErrObjectNotFound)
+}
+
+// PutVirtualAp creates or updates a virtual_ap at configPath and reads it back,
+// returning ErrNotApplied if the profile does not have the values that were written
+func (c *Client) PutVirtualAp(ctx context.Context, configPath string, v VirtualAp) error {
+	if _, err := c.PostObjects(ctx, configPath, []ObjectAction{{Name: "virtual_ap", Data: v}}); err != nil {
+		return err
+	}
+	got, err := c.GetVirtualAp(ctx, configPath, v.Name)
+	if err != nil {
+		return err
+	}
+	if !v.applied(got) {
+		return fmt.Errorf("virtual_ap %s: %w", v.Name, ErrNotApplied)
+	}
+	return nil
+}
+
+// DeleteVirtualAp deletes the virtual_ap called name at configPath
+func (c *Client) DeleteVirtualAp(ctx context.Context, configPath, name string) error {
+	return c.deleteProfile(ctx, "virtual_ap", configPath, name)
+}
+
+// ListAAAProfiles returns all aaa_prof at configPath
+func (c *Client) ListAAAProfiles(ctx context.Context, configPath string) ([]AAAProfile, error) {
+	var profiles []AAAProfile
+	err := c.getProfiles(ctx, "aaa_prof", configPath, "", &profiles)
+	return profiles, err
+}
+
+// GetAAAProfile returns the aaa_prof called name at configPath, ErrObjectNotFound if it does not exist
+func (c *Client) GetAAAProfile(ctx context.Context, configPath, name string) (AAAProfile, error) {
+	var profiles []AAAProfile
+	if err := c.getProfiles(ctx, "aaa_prof", configPath, name, &profiles); err != nil {
+		return AAAProfile{}, err
+	}
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return AAAProfile{}, fmt.Errorf("aaa_prof %s: %w", name, ErrObjectNotFound)
+}
+
+// PutAAAProfile creates or updates an aaa_prof at configPath and reads it back,
+// returning ErrNotApplied if the profile does not have the values that were written
+func (c *Client) PutAAAProfile(ctx context.Context, configPath string, a AAAProfile) error {
+	if _, err := c.PostObjects(ctx, configPath, []ObjectAction{{Name: "aaa_prof", Data: a}}); err != nil {
+		return err
+	}
+	got, err := c.GetAAAProfile(ctx, configPath, a.Name)
+	if err != nil {
+		return err
+	}
+	if !a.applied(got) {
+		return fmt.Errorf("aaa_prof %s: %w", a.Name, ErrNotApplied)
+	}
+	return nil
+}
+
+// DeleteAAAProfile deletes the aaa_prof called name at configPath
+func (c *Client) DeleteAAAProfile(ctx context.Context, configPath, name string) error {
+	return c.deleteProfile(ctx, "aaa_prof", configPath, name)
+}
+
+// EnableVirtualAp adds the virtual AP to an AP group and checks that the group now has it
+func (c *Client) EnableVirtualAp(ctx context.Context, configPath, group, vap string) error {
+	if err := c.AttachVirtualAp(ctx, configPath, group, vap); err != nil {
+		return err
+	}
+	return c.verifyGroupVap(ctx, configPath, group, vap, true)
+}
+
+// DisableVirtualAp removes the virtual AP from an AP group and checks that the group no longer has it
+func (c *Client) DisableVirtualAp(ctx context.Context, configPath, group, vap string) error {
+	if err := c.DetachVirtualAp(ctx, configPath, group, vap); err != nil {
+		return err
+	}
+	return c.verifyGroupVap(ctx, configPath, group, vap, false)
+}
+
+// verifyGroupVap reads back the AP group and checks if vap is in it
+func (c *Client) verifyGroupVap(ctx context.Context, configPath, group, vap string, want bool) error {
+	g, err := c.GetApGroup(ctx, configPath, group)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, ref := range g.VirtualAps {
+		found = found || ref.Name == vap
+	}
+	if found != want {
+		return fmt.Errorf("virtual_ap %s in ap_group %s: %w", vap, group, ErrNotApplied)
+	}
+	return nil
+}